@@ -0,0 +1,384 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// AvroRecord is an Avro record schema.
+type AvroRecord struct {
+	Type      string       `json:"type"`
+	Name      string       `json:"name"`
+	Namespace string       `json:"namespace,omitempty"`
+	Doc       string       `json:"doc,omitempty"`
+	Fields    []*AvroField `json:"fields"`
+}
+
+// AvroField is a single field of an Avro record.
+type AvroField struct {
+	Name    string          `json:"name"`
+	Doc     string          `json:"doc,omitempty"`
+	Type    interface{}     `json:"type"`
+	Default json.RawMessage `json:"default,omitempty"`
+}
+
+// AvroArray is an Avro array schema.
+type AvroArray struct {
+	Type  string      `json:"type"`
+	Items interface{} `json:"items"`
+}
+
+// AvroMap is an Avro map schema.
+type AvroMap struct {
+	Type   string      `json:"type"`
+	Values interface{} `json:"values"`
+}
+
+// AvroEnum is an Avro enum schema.
+type AvroEnum struct {
+	Type      string   `json:"type"`
+	Name      string   `json:"name"`
+	Namespace string   `json:"namespace,omitempty"`
+	Symbols   []string `json:"symbols"`
+}
+
+// AvroLogical is an Avro primitive annotated with a logical type.
+type AvroLogical struct {
+	Type        string `json:"type"`
+	LogicalType string `json:"logicalType"`
+	Precision   int    `json:"precision,omitempty"`
+}
+
+var (
+	avroNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	// Record names may be full names, such as those of types renamed to
+	// tell them apart.
+	avroFullNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*$`)
+)
+
+// ReflectAvro reflects v into an Avro schema.
+//
+// Structs become records named with the Reflector's type names, and are only
+// defined once; later uses refer to them by name. With FullyQualifyTypeNames,
+// the package path becomes the record's namespace. Anonymous structs are
+// named after the fields holding them, in the namespace of the record that
+// holds them. Optional and nullable fields become unions with null, slices
+// become arrays, maps become maps, string enum tags become enums, time.Time
+// becomes a timestamp-millis long and uint64 becomes a decimal, as it does not
+// fit a long. An optional field with a default tag lists its own type first in
+// the union, so that the default is valid.
+//
+// Types mapped by TypeMapper or implementing JSONSchemaType are used when
+// their schema is a plain string, integer, number or boolean; other custom
+// schemas and IgnoredTypes cannot be represented, and are reported as errors.
+//
+// The result is one of the Avro* types or a primitive type name, ready to be
+// marshalled to JSON.
+func (r *Reflector) ReflectAvro(v interface{}) (interface{}, error) {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	var schema interface{}
+	_, err := r.reflectNamed(func(r *Reflector) error {
+		a := &avroReflector{r: r, defined: map[string]bool{}}
+		var err error
+		schema, err = a.reflect(t, "")
+		return err
+	})
+	return schema, err
+}
+
+type avroReflector struct {
+	r       *Reflector
+	defined map[string]bool
+	// namespace is that of the record whose fields are being reflected.
+	namespace string
+}
+
+func (a *avroReflector) reflect(t reflect.Type, path string) (interface{}, error) {
+	if st := a.customType(t); st != nil {
+		return avroPrimitive(st, path, t)
+	}
+	for _, ignored := range a.r.IgnoredTypes {
+		if reflect.TypeOf(ignored) == t {
+			return nil, fmt.Errorf("%s: ignored types cannot be represented in Avro", avroPath(path, t))
+		}
+	}
+
+	switch t {
+	case timeType:
+		return &AvroLogical{Type: "long", LogicalType: "timestamp-millis"}, nil
+	case ipType, uriType:
+		return "string", nil
+	case rawMessageType:
+		return nil, fmt.Errorf("%s: free-form JSON cannot be represented in Avro", avroPath(path, t))
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		inner, err := a.reflect(t.Elem(), path)
+		if err != nil {
+			return nil, err
+		}
+		return avroNullable(inner), nil
+	case reflect.Bool:
+		return "boolean", nil
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return "int", nil
+	case reflect.Int, reflect.Int64, reflect.Uint32:
+		return "long", nil
+	case reflect.Uint, reflect.Uint64:
+		return &AvroLogical{Type: "bytes", LogicalType: "decimal", Precision: 20}, nil
+	case reflect.Float32:
+		return "float", nil
+	case reflect.Float64:
+		return "double", nil
+	case reflect.String:
+		return "string", nil
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 && t.Kind() == reflect.Slice {
+			return "bytes", nil
+		}
+		items, err := a.reflect(t.Elem(), path)
+		if err != nil {
+			return nil, err
+		}
+		return &AvroArray{Type: "array", Items: items}, nil
+	case reflect.Map:
+		switch t.Key().Kind() {
+		case reflect.String, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		default:
+			return nil, fmt.Errorf("%s: map keys must be strings or integers", avroPath(path, t))
+		}
+		values, err := a.reflect(t.Elem(), path)
+		if err != nil {
+			return nil, err
+		}
+		return &AvroMap{Type: "map", Values: values}, nil
+	case reflect.Struct:
+		return a.reflectRecord(t, path)
+	}
+	return nil, fmt.Errorf("%s: type cannot be represented in Avro", avroPath(path, t))
+}
+
+func (a *avroReflector) reflectRecord(t reflect.Type, path string) (interface{}, error) {
+	record := &AvroRecord{Type: "record", Fields: []*AvroField{}}
+	fullName := a.r.typeName(t)
+	a.r.names.add(fullName, t)
+	switch {
+	case t.Name() == "":
+		if path == "" {
+			return nil, fmt.Errorf("%s: anonymous structs are named after their field, and cannot be the top-level type", t)
+		}
+		record.Name = strings.ReplaceAll(path, ".", "")
+		record.Namespace = a.namespace
+		fullName = record.Name
+		if record.Namespace != "" {
+			fullName = record.Namespace + "." + record.Name
+		}
+	case a.r.FullyQualifyTypeNames && t.PkgPath() != "" && strings.HasPrefix(fullName, t.PkgPath()+"."):
+		record.Namespace = avroNamespace(t.PkgPath())
+		record.Name = strings.TrimPrefix(fullName, t.PkgPath()+".")
+		fullName = record.Namespace + "." + record.Name
+	default:
+		record.Name = fullName
+	}
+	if !avroFullNamePattern.MatchString(fullName) {
+		return nil, fmt.Errorf("%s: %q is not a valid Avro name", t, fullName)
+	}
+	if a.defined[fullName] {
+		return fullName, nil
+	}
+	a.defined[fullName] = true
+	namespace := a.namespace
+	a.namespace = record.Namespace
+	defer func() { a.namespace = namespace }()
+	if err := a.reflectFields(record, t); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// customType returns the schema TypeMapper or a JSONSchemaType method gives
+// t, if any.
+func (a *avroReflector) customType(t reflect.Type) *Type {
+	if a.r.TypeMapper != nil {
+		if st := a.r.TypeMapper(t); st != nil {
+			return st
+		}
+	}
+	if t.Kind() != reflect.Ptr && t.Implements(customType) {
+		return reflect.New(t).Interface().(customSchemaType).JSONSchemaType()
+	}
+	return nil
+}
+
+// reflectFields mirrors reflectStructFields, adding a field to record for
+// every property of t.
+func (a *avroReflector) reflectFields(record *AvroRecord, t reflect.Type) error {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	handleField := func(f reflect.StructField) error {
+		name, shouldEmbed, required, nullable := a.r.reflectFieldName(f)
+		if name == "" {
+			if shouldEmbed {
+				return a.reflectFields(record, f.Type)
+			}
+			return nil
+		}
+		if !avroNamePattern.MatchString(name) {
+			return fmt.Errorf("%s.%s: %q is not a valid Avro name", record.Name, f.Name, name)
+		}
+
+		// Reuse the JSON Schema keywords from tags for enums, docs and defaults.
+		property := keywordsFromTags(f, name)
+
+		field := &AvroField{Name: name, Doc: property.Description}
+		var err error
+		if f.Type.Kind() == reflect.String && len(property.Enum) > 0 {
+			field.Type, err = a.reflectEnum(record.Name+upperFirst(name), property.Enum)
+		} else {
+			field.Type, err = a.reflect(f.Type, record.Name+"."+f.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if !required || nullable {
+			field.Type = avroNullable(field.Type)
+		}
+		// A union's default must match its first branch.
+		if property.Default != nil {
+			field.Type = avroNullLast(field.Type)
+			field.Default, err = json.Marshal(property.Default)
+			if err != nil {
+				return err
+			}
+		} else if _, ok := field.Type.([]interface{}); ok {
+			field.Default = json.RawMessage("null")
+		}
+		record.Fields = append(record.Fields, field)
+		return nil
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		if err := handleField(t.Field(i)); err != nil {
+			return err
+		}
+	}
+	if a.r.AdditionalFields != nil {
+		for _, sf := range a.r.AdditionalFields(t) {
+			if err := handleField(sf); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (a *avroReflector) reflectEnum(name string, values []interface{}) (interface{}, error) {
+	enum := &AvroEnum{Type: "enum", Name: name, Namespace: a.namespace}
+	fullName := name
+	if enum.Namespace != "" {
+		fullName = enum.Namespace + "." + name
+	}
+	if a.defined[fullName] {
+		return fullName, nil
+	}
+	a.defined[fullName] = true
+	for _, v := range values {
+		symbol := fmt.Sprint(v)
+		if !avroNamePattern.MatchString(symbol) {
+			return nil, fmt.Errorf("%s: %q is not a valid Avro enum symbol", name, symbol)
+		}
+		enum.Symbols = append(enum.Symbols, symbol)
+	}
+	return enum, nil
+}
+
+// avroNullable makes t a union with null, unless it already is one.
+func avroNullable(t interface{}) interface{} {
+	if union, ok := t.([]interface{}); ok {
+		return union
+	}
+	return []interface{}{"null", t}
+}
+
+// avroNullLast moves null to the end of a union, so that a default value can
+// match its first branch.
+func avroNullLast(t interface{}) interface{} {
+	union, ok := t.([]interface{})
+	if !ok {
+		return t
+	}
+	reordered := make([]interface{}, 0, len(union))
+	for _, branch := range union {
+		if branch != "null" {
+			reordered = append(reordered, branch)
+		}
+	}
+	if len(reordered) < len(union) {
+		reordered = append(reordered, "null")
+	}
+	return reordered
+}
+
+// avroPrimitive converts a custom schema to an Avro primitive, if it is a
+// plain JSON Schema primitive.
+func avroPrimitive(st *Type, path string, t reflect.Type) (interface{}, error) {
+	switch {
+	case st.Ref != "" || st.Enum != nil || st.OneOf != nil || st.AnyOf != nil || st.AllOf != nil:
+	case st.Type == "string" && st.Format == "date-time":
+		return &AvroLogical{Type: "long", LogicalType: "timestamp-millis"}, nil
+	case st.Type == "string":
+		return "string", nil
+	case st.Type == "integer":
+		return "long", nil
+	case st.Type == "number":
+		return "double", nil
+	case st.Type == "boolean":
+		return "boolean", nil
+	}
+	return nil, fmt.Errorf("%s: custom schema cannot be represented in Avro", avroPath(path, t))
+}
+
+// avroNamespace turns a Go package path into an Avro namespace, such as
+// github.com/x/y-z into github.com.x.y_z.
+func avroNamespace(pkgPath string) string {
+	var parts []string
+	for _, part := range strings.FieldsFunc(pkgPath, func(r rune) bool { return r == '/' || r == '.' }) {
+		part = strings.Map(func(r rune) rune {
+			if r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+				return r
+			}
+			return '_'
+		}, part)
+		if unicode.IsDigit(rune(part[0])) {
+			part = "_" + part
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ".")
+}
+
+func upperFirst(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[size:]
+}
+
+func avroPath(path string, t reflect.Type) string {
+	if path == "" {
+		return t.String()
+	}
+	return path
+}
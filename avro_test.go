@@ -0,0 +1,145 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type AvroBase struct {
+	ID string `json:"id" jsonschema:"description=event id"`
+}
+
+type AvroOrder struct {
+	AvroBase
+	Status    string            `json:"status" jsonschema:"enum=pending,enum=shipped,default=pending"`
+	Quantity  int32             `json:"quantity" jsonschema:"default=1"`
+	Total     float64           `json:"total"`
+	Paid      bool              `json:"paid"`
+	Note      string            `json:"note,omitempty"`
+	Tags      []string          `json:"tags"`
+	Labels    map[string]string `json:"labels"`
+	CreatedAt time.Time         `json:"created_at"`
+	Raw       []byte            `json:"raw"`
+	Billing   *AvroAddress      `json:"billing"`
+	Shipping  AvroAddress       `json:"shipping"`
+	Previous  *AvroOrder        `json:"previous,omitempty"`
+}
+
+type AvroAddress struct {
+	Street string `json:"street"`
+}
+
+type AvroUnsupported struct {
+	Any interface{} `json:"any"`
+}
+
+func TestReflectAvro(t *testing.T) {
+	actual, err := (&Reflector{}).ReflectAvro(&AvroOrder{})
+	require.NoError(t, err)
+	actualJSON, err := json.MarshalIndent(actual, "", "  ")
+	require.NoError(t, err)
+	expectedJSON, err := ioutil.ReadFile("fixtures/avro.json")
+	require.NoError(t, err)
+	require.JSONEq(t, string(expectedJSON), string(actualJSON))
+}
+
+func TestReflectAvroUnsupported(t *testing.T) {
+	_, err := (&Reflector{}).ReflectAvro(&AvroUnsupported{})
+	require.EqualError(t, err, "AvroUnsupported.Any: type cannot be represented in Avro")
+}
+
+type AvroShipment struct {
+	Address AvroAddress `json:"address"`
+	Return  AvroAddress `json:"return"`
+	Parcel  struct {
+		Weight float64 `json:"weight"`
+	} `json:"parcel"`
+	Items []struct {
+		SKU string `json:"sku"`
+	} `json:"items"`
+}
+
+func TestReflectAvroNames(t *testing.T) {
+	actual, err := (&Reflector{FullyQualifyTypeNames: true}).ReflectAvro(&AvroShipment{})
+	require.NoError(t, err)
+	actualJSON, err := json.Marshal(actual)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"type": "record",
+		"name": "AvroShipment",
+		"namespace": "github.com.alecthomas.jsonschema",
+		"fields": [
+			{"name": "address", "type": {"type": "record", "name": "AvroAddress", "namespace": "github.com.alecthomas.jsonschema", "fields": [{"name": "street", "type": "string"}]}},
+			{"name": "return", "type": "github.com.alecthomas.jsonschema.AvroAddress"},
+			{"name": "parcel", "type": {"type": "record", "name": "AvroShipmentParcel", "namespace": "github.com.alecthomas.jsonschema", "fields": [{"name": "weight", "type": "double"}]}},
+			{"name": "items", "type": {"type": "array", "items": {"type": "record", "name": "AvroShipmentItems", "namespace": "github.com.alecthomas.jsonschema", "fields": [{"name": "sku", "type": "string"}]}}}
+		]
+	}`, string(actualJSON))
+
+	_, err = (&Reflector{}).ReflectAvro(&struct{ X int }{})
+	require.EqualError(t, err, "struct { X int }: anonymous structs are named after their field, and cannot be the top-level type")
+
+	require.Equal(t, "gopkg.in.yaml.v3", avroNamespace("gopkg.in/yaml.v3"))
+	require.Equal(t, "example.com.x_y._2d", avroNamespace("example.com/x-y/2d"))
+}
+
+type AvroCelsius float64
+
+func (AvroCelsius) JSONSchemaType() *Type {
+	return &Type{Type: "number"}
+}
+
+type AvroPoint struct{ X, Y int }
+
+func (AvroPoint) JSONSchemaType() *Type {
+	return &Type{Type: "array", Items: &Type{Type: "integer"}}
+}
+
+type AvroReading struct {
+	Count       uint64       `json:"count"`
+	Unit        *string      `json:"unit,omitempty" jsonschema:"default=C"`
+	Limit       int          `json:"limit,omitempty" jsonschema:"default=10"`
+	Temperature AvroCelsius  `json:"temperature"`
+	Duration    AvroDuration `json:"duration"`
+}
+
+type AvroDuration int64
+
+type AvroLocated struct {
+	Point AvroPoint `json:"point"`
+}
+
+func TestReflectAvroTypes(t *testing.T) {
+	r := &Reflector{TypeMapper: func(t reflect.Type) *Type {
+		if t == reflect.TypeOf(AvroDuration(0)) {
+			return &Type{Type: "string", Format: "duration"}
+		}
+		return nil
+	}}
+	actual, err := r.ReflectAvro(&AvroReading{})
+	require.NoError(t, err)
+	actualJSON, err := json.Marshal(actual)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"type": "record",
+		"name": "AvroReading",
+		"fields": [
+			{"name": "count", "type": {"type": "bytes", "logicalType": "decimal", "precision": 20}},
+			{"name": "unit", "type": ["string", "null"], "default": "C"},
+			{"name": "limit", "type": ["long", "null"], "default": 10},
+			{"name": "temperature", "type": "double"},
+			{"name": "duration", "type": "string"}
+		]
+	}`, string(actualJSON))
+
+	_, err = r.ReflectAvro(&AvroLocated{})
+	require.EqualError(t, err, "AvroLocated.Point: custom schema cannot be represented in Avro")
+
+	_, err = (&Reflector{IgnoredTypes: []interface{}{AvroAddress{}}}).ReflectAvro(&AvroShipment{})
+	require.EqualError(t, err, "AvroShipment.Address: ignored types cannot be represented in Avro")
+}
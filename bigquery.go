@@ -0,0 +1,132 @@
+package jsonschema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BigQueryField is a column of a BigQuery table schema.
+type BigQueryField struct {
+	Name        string           `json:"name"`
+	Type        string           `json:"type"`
+	Mode        string           `json:"mode"`
+	Description string           `json:"description,omitempty"`
+	Fields      []*BigQueryField `json:"fields,omitempty"`
+}
+
+// BigQuerySchema converts s, which must describe an object, into the fields
+// of a BigQuery table schema.
+//
+// Required properties are REQUIRED, optional and nullable ones NULLABLE and
+// arrays REPEATED. Nested objects become RECORDs, while maps and free-form
+// values become JSON columns. Arrays of arrays and recursive types cannot be
+// represented.
+func BigQuerySchema(s *Schema) ([]*BigQueryField, error) {
+	c := &bigQueryConverter{definitions: s.Definitions, visiting: map[*Type]bool{}}
+	root := s.Definitions.resolve(s.Type)
+	if root == nil || root.Type != "object" || root.Properties == nil {
+		return nil, fmt.Errorf("root schema must be an object with properties")
+	}
+	return c.fields(root, "")
+}
+
+type bigQueryConverter struct {
+	definitions Definitions
+	visiting    map[*Type]bool
+}
+
+func (c *bigQueryConverter) fields(t *Type, path string) ([]*BigQueryField, error) {
+	if c.visiting[t] {
+		return nil, fmt.Errorf("%s: recursive types cannot be represented", path)
+	}
+	c.visiting[t] = true
+	defer delete(c.visiting, t)
+
+	fields := []*BigQueryField{}
+	for _, key := range t.Properties.Keys() {
+		v, _ := t.Properties.Get(key)
+		p, ok := v.(*Type)
+		if !ok {
+			return nil, fmt.Errorf("%s.%s: property is not a schema", path, key)
+		}
+		mode := "NULLABLE"
+		if contains(t.Required, key) {
+			mode = "REQUIRED"
+		}
+		field, err := c.field(key, p, mode, strings.TrimPrefix(path+"."+key, "."))
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+func (c *bigQueryConverter) field(name string, t *Type, mode, path string) (*BigQueryField, error) {
+	description := t.Description
+	if sub, ok := nullableOneOf(t); ok {
+		t = sub
+		mode = "NULLABLE"
+	}
+	t = c.definitions.resolve(t)
+	if t == nil {
+		return nil, fmt.Errorf("%s: unresolvable $ref", path)
+	}
+	if description == "" {
+		description = t.Description
+	}
+	field := &BigQueryField{Name: name, Mode: mode, Description: description}
+
+	if t.Type == "array" {
+		if t.Items == nil {
+			field.Type = "JSON"
+			return field, nil
+		}
+		items := c.definitions.resolve(t.Items)
+		if items != nil && items.Type == "array" {
+			return nil, fmt.Errorf("%s: arrays of arrays cannot be represented", path)
+		}
+		repeated, err := c.field(name, t.Items, "REPEATED", path)
+		if err != nil {
+			return nil, err
+		}
+		repeated.Mode = "REPEATED"
+		if repeated.Description == "" {
+			repeated.Description = description
+		}
+		return repeated, nil
+	}
+
+	switch t.Type {
+	case "string":
+		field.Type = "STRING"
+		switch {
+		case t.Format == "date-time":
+			field.Type = "TIMESTAMP"
+		case t.Format == "date":
+			field.Type = "DATE"
+		case t.Media != nil && t.Media.BinaryEncoding == "base64":
+			field.Type = "BYTES"
+		}
+	case "integer":
+		field.Type = "INTEGER"
+	case "number":
+		field.Type = "FLOAT"
+	case "boolean":
+		field.Type = "BOOLEAN"
+	case "object":
+		if t.Properties == nil || len(t.Properties.Keys()) == 0 {
+			field.Type = "JSON"
+			break
+		}
+		fields, err := c.fields(t, path)
+		if err != nil {
+			return nil, err
+		}
+		field.Type = "RECORD"
+		field.Fields = fields
+	default:
+		field.Type = "JSON"
+	}
+	return field, nil
+}
@@ -0,0 +1,52 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type TableRow struct {
+	ID        int64             `json:"id"`
+	Name      string            `json:"name" jsonschema:"description=display name"`
+	Status    string            `json:"status,omitempty" jsonschema:"enum=active,enum=it's off"`
+	Score     float64           `json:"score" jsonschema:"nullable"`
+	Active    bool              `json:"active"`
+	CreatedAt time.Time         `json:"created_at"`
+	Avatar    []byte            `json:"avatar,omitempty"`
+	Tags      []string          `json:"tags"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Address   TableAddress      `json:"address"`
+	History   []TableAddress    `json:"history,omitempty"`
+}
+
+type TableAddress struct {
+	Street string `json:"street"`
+	City   string `json:"city,omitempty"`
+}
+
+type MatrixRow struct {
+	Cells [][]int `json:"cells"`
+}
+
+type RecursiveRow struct {
+	Children []RecursiveRow `json:"children"`
+}
+
+func TestBigQuerySchema(t *testing.T) {
+	actual, err := BigQuerySchema(Reflect(&TableRow{}))
+	require.NoError(t, err)
+	actualJSON, err := json.MarshalIndent(actual, "", "  ")
+	require.NoError(t, err)
+	expectedJSON, err := ioutil.ReadFile("fixtures/bigquery.json")
+	require.NoError(t, err)
+	require.JSONEq(t, string(expectedJSON), string(actualJSON))
+
+	_, err = BigQuerySchema(Reflect(&RecursiveRow{}))
+	require.EqualError(t, err, "children: recursive types cannot be represented")
+	_, err = BigQuerySchema(Reflect(&MatrixRow{}))
+	require.EqualError(t, err, "cells: arrays of arrays cannot be represented")
+}
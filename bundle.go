@@ -0,0 +1,288 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// A Bundler inlines referenced schemas into a single document, loading the
+// targets of absolute URI references with its Loader.
+type Bundler struct {
+	// Loader loads schemas referenced by absolute URI. Defaults to
+	// MetaSchemas.
+	Loader Loader
+}
+
+// Bundle loads the schema in the file name of fsys and inlines every schema it
+// references, using the default Bundler.
+func Bundle(fsys fs.FS, name string) (*Schema, error) {
+	return (&Bundler{}).Bundle(fsys, name)
+}
+
+// Bundle loads the schema in the file name of fsys and inlines every schema it
+// references in other files, producing a single document.
+//
+// References may point to another file ("./User.json"), to a definition in
+// another file ("common.json#/definitions/ID") or, within a file, to its own
+// definitions or root. They are resolved relative to the referring file.
+// Absolute URIs, and references relative to a schema loaded by URI, are
+// loaded with the Loader. The
+// referenced schemas are added to the definitions of the result, named after
+// their definition or file, with a numeric suffix when names collide, and the
+// references are rewritten to local "#/definitions/" pointers.
+//
+// A definition of the root file that only refers to another file, as in an
+// index produced by SplitFiles, is replaced by the referenced schema.
+func (bd *Bundler) Bundle(fsys fs.FS, name string) (*Schema, error) {
+	loader := bd.Loader
+	if loader == nil {
+		loader = MetaSchemas
+	}
+	b := &bundler{
+		fsys:   fsys,
+		loader: loader,
+		files:  map[string]*Type{},
+		names:  map[string]string{},
+		used:   map[string]bool{},
+		defs:   Definitions{},
+	}
+	root, err := b.load(name)
+	if err != nil {
+		return nil, err
+	}
+	rootType := root.clone()
+	definitions := rootType.Definitions
+	rootType.Definitions = nil
+
+	for _, def := range sortedKeys(definitions) {
+		b.used[def] = true
+		b.names[bundleKey(name, "/definitions/"+def)] = def
+	}
+	for _, def := range sortedKeys(definitions) {
+		t := definitions[def]
+		if file, fragment, ok := splitRef(t.Ref); ok && file != "" && isRefOnly(t) {
+			target, err := b.target(name, file)
+			if err != nil {
+				return nil, err
+			}
+			key := bundleKey(target, fragment)
+			if _, ok := b.names[key]; !ok {
+				b.names[key] = def
+				resolved, resolvedFile, err := b.resolve(name, t.Ref)
+				if err != nil {
+					return nil, err
+				}
+				b.add(def, resolved, resolvedFile)
+				continue
+			}
+		}
+		b.add(def, t, name)
+	}
+	b.queue = append(b.queue, bundleItem{rootType, name})
+
+	for len(b.queue) > 0 {
+		item := b.queue[0]
+		b.queue = b.queue[1:]
+		var err error
+		item.t.walk(func(t *Type) {
+			if t.Ref == "" || err != nil {
+				return
+			}
+			t.Ref, err = b.ref(item.file, t.Ref, name)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	s := &Schema{Type: rootType}
+	if len(b.defs) > 0 {
+		s.Definitions = b.defs
+	}
+	return s, nil
+}
+
+type bundler struct {
+	fsys   fs.FS
+	loader Loader
+	// files holds the loaded schemas by file name or absolute URI.
+	files map[string]*Type
+	// names maps a file and fragment to the definition it is bundled as.
+	names map[string]string
+	used  map[string]bool
+	defs  Definitions
+	queue []bundleItem
+}
+
+// bundleItem is a schema whose references are relative to file.
+type bundleItem struct {
+	t    *Type
+	file string
+}
+
+func bundleKey(file, fragment string) string {
+	if fragment == "/" {
+		fragment = ""
+	}
+	return file + "#" + fragment
+}
+
+func (b *bundler) load(name string) (*Type, error) {
+	if t, ok := b.files[name]; ok {
+		return t, nil
+	}
+	if isAbsoluteURI(name) {
+		s, err := b.loader.Load(name)
+		if err != nil {
+			return nil, err
+		}
+		t := &Type{}
+		if s.Type != nil {
+			t = s.Type.clone()
+		}
+		t.Definitions = s.Definitions
+		b.files[name] = t
+		return t, nil
+	}
+	data, err := fs.ReadFile(b.fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("jsonschema: %w", err)
+	}
+	t := &Type{}
+	if err := json.Unmarshal(data, t); err != nil {
+		return nil, fmt.Errorf("jsonschema: %s: %w", name, err)
+	}
+	b.files[name] = t
+	return t, nil
+}
+
+// add bundles t, a schema from file, as the definition name.
+func (b *bundler) add(name string, t *Type, file string) {
+	t = t.clone()
+	t.ID = ""
+	delete(t.Extras, "id")
+	t.Version = ""
+	// Definitions of other files are only bundled once referenced.
+	t.Definitions = nil
+	b.used[name] = true
+	b.defs[name] = t
+	b.queue = append(b.queue, bundleItem{t, file})
+}
+
+// ref returns the local reference that replaces ref, found in file.
+func (b *bundler) ref(file, ref, root string) (string, error) {
+	refFile, fragment, ok := splitRef(ref)
+	if !ok {
+		return "", fmt.Errorf("jsonschema: %s: only relative file references and absolute URIs are supported, not %s", file, ref)
+	}
+	target, err := b.target(file, refFile)
+	if err != nil {
+		return "", err
+	}
+	if target == root && (fragment == "" || fragment == "/") {
+		return "#", nil
+	}
+	key := bundleKey(target, fragment)
+	if name, ok := b.names[key]; ok {
+		return "#/definitions/" + name, nil
+	}
+
+	t, _, err := b.resolve(file, ref)
+	if err != nil {
+		return "", err
+	}
+	base := target
+	if u, err := url.Parse(target); err == nil && u.IsAbs() {
+		base = u.Path
+	}
+	name := strings.TrimSuffix(path.Base(base), path.Ext(base))
+	if def := strings.TrimPrefix(fragment, "/definitions/"); def != fragment {
+		name = pointerUnescape(def)
+	}
+	name = b.unique(name)
+	b.names[key] = name
+	b.add(name, t, target)
+	return "#/definitions/" + name, nil
+}
+
+// target returns the file name or absolute URI that refFile, referenced from
+// file, refers to.
+func (b *bundler) target(file, refFile string) (string, error) {
+	switch {
+	case refFile == "":
+		return file, nil
+	case isAbsoluteURI(refFile):
+		return refFile, nil
+	case isAbsoluteURI(file):
+		uri, err := resolveURI(file, refFile)
+		if err != nil {
+			return "", fmt.Errorf("jsonschema: %s: invalid reference %s: %w", file, refFile, err)
+		}
+		return uri, nil
+	}
+	return path.Join(path.Dir(file), refFile), nil
+}
+
+// resolve loads the schema ref points to from file, and the file it is in.
+func (b *bundler) resolve(file, ref string) (*Type, string, error) {
+	refFile, fragment, _ := splitRef(ref)
+	target, err := b.target(file, refFile)
+	if err != nil {
+		return nil, "", err
+	}
+	if !isAbsoluteURI(target) && !fs.ValidPath(target) {
+		return nil, "", fmt.Errorf("jsonschema: %s: reference %s is outside of the file system", file, ref)
+	}
+	t, err := b.load(target)
+	if err != nil {
+		return nil, "", err
+	}
+	switch {
+	case fragment == "" || fragment == "/":
+		return t, target, nil
+	case strings.HasPrefix(fragment, "/definitions/"):
+		def := pointerUnescape(strings.TrimPrefix(fragment, "/definitions/"))
+		if sub, ok := t.Definitions[def]; ok {
+			return sub, target, nil
+		}
+	}
+	return nil, "", fmt.Errorf("jsonschema: %s: cannot resolve reference %s", file, ref)
+}
+
+func (b *bundler) unique(name string) string {
+	candidate := name
+	for i := 2; b.used[candidate]; i++ {
+		candidate = name + "_" + strconv.Itoa(i)
+	}
+	return candidate
+}
+
+// splitRef splits a reference into its file or URI and JSON Pointer
+// fragment. It reports false for absolute paths.
+func splitRef(ref string) (string, string, bool) {
+	file, fragment := ref, ""
+	if i := strings.Index(ref, "#"); i >= 0 {
+		file, fragment = ref[:i], ref[i+1:]
+	}
+	if strings.HasPrefix(file, "/") || (strings.Contains(file, ":") && !isAbsoluteURI(file)) {
+		return "", "", false
+	}
+	return file, fragment, true
+}
+
+// isRefOnly reports whether t has no keywords besides $ref and $schema.
+func isRefOnly(t *Type) bool {
+	c := *t
+	c.Ref, c.Version = "", ""
+	return reflect.DeepEqual(c, Type{})
+}
+
+func pointerUnescape(token string) string {
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+}
@@ -0,0 +1,148 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundleSplitFiles(t *testing.T) {
+	s, err := Bundle(os.DirFS("fixtures/split"), SplitIndex)
+	require.NoError(t, err)
+	expected, err := json.Marshal(Reflect(&SplitUser{}))
+	require.NoError(t, err)
+	actual, err := json.Marshal(s)
+	require.NoError(t, err)
+	require.JSONEq(t, string(expected), string(actual))
+}
+
+func TestBundle(t *testing.T) {
+	fsys := fstest.MapFS{
+		"api/order.json": {Data: []byte(`{
+			"$schema": "http://json-schema.org/draft-04/schema#",
+			"type": "object",
+			"properties": {
+				"id": {"$ref": "../common/types.json#/definitions/ID"},
+				"customer": {"$ref": "customer.json"},
+				"lines": {"type": "array", "items": {"$ref": "#/definitions/Line"}},
+				"parent": {"$ref": "#"}
+			},
+			"definitions": {
+				"Line": {"type": "object", "properties": {"sku": {"$ref": "../common/types.json#/definitions/SKU"}}},
+				"ID": {"type": "integer"}
+			}
+		}`)},
+		"api/customer.json": {Data: []byte(`{
+			"$id": "https://example.com/customer.json",
+			"type": "object",
+			"properties": {
+				"id": {"$ref": "../common/types.json#/definitions/ID"},
+				"orders": {"type": "array", "items": {"$ref": "order.json"}}
+			}
+		}`)},
+		"common/types.json": {Data: []byte(`{
+			"definitions": {
+				"ID": {"type": "string", "format": "uuid"},
+				"SKU": {"type": "string", "pattern": "^[A-Z]+$"}
+			}
+		}`)},
+	}
+	s, err := Bundle(fsys, "api/order.json")
+	require.NoError(t, err)
+	b, err := json.Marshal(s)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"$schema": "http://json-schema.org/draft-04/schema#",
+		"type": "object",
+		"properties": {
+			"id": {"$ref": "#/definitions/ID_2"},
+			"customer": {"$ref": "#/definitions/customer"},
+			"lines": {"type": "array", "items": {"$ref": "#/definitions/Line"}},
+			"parent": {"$ref": "#"}
+		},
+		"definitions": {
+			"ID": {"type": "integer"},
+			"ID_2": {"type": "string", "format": "uuid"},
+			"Line": {"type": "object", "properties": {"sku": {"$ref": "#/definitions/SKU"}}},
+			"SKU": {"type": "string", "pattern": "^[A-Z]+$"},
+			"customer": {
+				"type": "object",
+				"properties": {
+					"id": {"$ref": "#/definitions/ID_2"},
+					"orders": {"type": "array", "items": {"$ref": "#"}}
+				}
+			}
+		}
+	}`, string(b))
+}
+
+func TestBundleErrors(t *testing.T) {
+	fsys := fstest.MapFS{
+		"remote.json":   {Data: []byte(`{"$ref": "https://example.com/schema.json"}`)},
+		"absolute.json": {Data: []byte(`{"$ref": "/schema.json"}`)},
+		"outside.json":  {Data: []byte(`{"$ref": "../schema.json"}`)},
+		"missing.json":  {Data: []byte(`{"$ref": "other.json"}`)},
+		"fragment.json": {Data: []byte(`{"$ref": "#/properties/x"}`)},
+		"invalid.json":  {Data: []byte(`{`)},
+	}
+	for file, msg := range map[string]string{
+		"remote.json":   "jsonschema: schema not found: https://example.com/schema.json",
+		"absolute.json": "jsonschema: absolute.json: only relative file references and absolute URIs are supported, not /schema.json",
+		"outside.json":  "jsonschema: outside.json: reference ../schema.json is outside of the file system",
+		"missing.json":  "jsonschema: open other.json: file does not exist",
+		"fragment.json": "jsonschema: fragment.json: cannot resolve reference #/properties/x",
+		"invalid.json":  "jsonschema: invalid.json: unexpected end of JSON input",
+		"none.json":     "jsonschema: open none.json: file does not exist",
+	} {
+		_, err := Bundle(fsys, file)
+		require.EqualError(t, err, msg, file)
+	}
+}
+
+func TestUnmarshalUnrepresentableKeywords(t *testing.T) {
+	for _, doc := range []string{
+		`{"type": "number", "maximum": 1.5}`,
+		`{"type": "number", "minimum": 0.5, "exclusiveMinimum": true}`,
+		`{"type": "number", "multipleOf": 0.01}`,
+		`{"type": "string", "maxLength": 1e1}`,
+		`{"type": "array", "items": [{"type": "string"}, {"type": "integer"}], "additionalItems": false}`,
+		`{"type": "array", "items": {"type": "string"}, "additionalItems": true}`,
+	} {
+		s := &Schema{}
+		require.NoError(t, json.Unmarshal([]byte(doc), s), doc)
+		b, err := json.Marshal(s)
+		require.NoError(t, err)
+		require.JSONEq(t, doc, string(b))
+	}
+
+	s := &Schema{}
+	require.NoError(t, json.Unmarshal([]byte(`{"type": "number", "maximum": 1.5}`), s))
+	require.NoError(t, Validate(s, []byte(`1.5`)))
+	require.Error(t, Validate(s, []byte(`1.6`)))
+}
+
+func TestUnmarshalBooleanSchemas(t *testing.T) {
+	s := &Schema{}
+	require.NoError(t, json.Unmarshal([]byte(`{"type": "object", "properties": {"any": true, "none": false}}`), s))
+	require.NoError(t, Validate(s, []byte(`{"any": [1]}`)))
+	require.Error(t, Validate(s, []byte(`{"none": 1}`)))
+}
+
+func TestBundleFractionalLimits(t *testing.T) {
+	fsys := fstest.MapFS{
+		"schema.json": {Data: []byte(`{"type": "object", "properties": {"ratio": {"$ref": "ratio.json"}}}`)},
+		"ratio.json":  {Data: []byte(`{"type": "number", "minimum": 0.5, "maximum": 1}`)},
+	}
+	s, err := Bundle(fsys, "schema.json")
+	require.NoError(t, err)
+	b, err := json.Marshal(s)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"type": "object",
+		"properties": {"ratio": {"$ref": "#/definitions/ratio"}},
+		"definitions": {"ratio": {"type": "number", "minimum": 0.5, "maximum": 1}}
+	}`, string(b))
+}
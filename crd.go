@@ -0,0 +1,200 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/iancoleman/orderedmap"
+)
+
+// ReflectCRD reflects v into a Kubernetes structural schema.
+// See CRDSchema for details.
+func (r *Reflector) ReflectCRD(v interface{}) (*Type, error) {
+	return CRDSchema(r.Reflect(v))
+}
+
+// CRDSchema converts s into an OpenAPI v3 structural schema suitable for the
+// openAPIV3Schema of a Kubernetes CustomResourceDefinition:
+//
+//   - all $refs are inlined, so recursive types are rejected;
+//   - maps become additionalProperties, and additionalProperties: false is dropped;
+//   - free-form values (interface{}, json.RawMessage) and structs allowing
+//     additional properties are marked x-kubernetes-preserve-unknown-fields;
+//   - a oneOf of string and integer becomes x-kubernetes-int-or-string;
+//   - a oneOf with null (from the nullable tag) becomes nullable;
+//   - byte slices become strings with the byte format.
+//
+// Any other oneOf, anyOf, allOf or not may only contain value validations.
+// Constructs that are not structural are reported in a *ConversionError.
+func CRDSchema(s *Schema) (*Type, error) {
+	c := &crd{inliner: newInliner(s.Definitions)}
+	out := c.convert(s.Type, "")
+	if out.Type != "object" {
+		c.problem("", "root schema must be an object")
+	}
+	if err := c.asError("a structural schema"); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+type crd struct {
+	inliner
+}
+
+func (c *crd) convert(t *Type, path string) *Type {
+	if t.Ref != "" {
+		return c.inline(t, path, c.convert)
+	}
+
+	if sub, ok := nullableOneOf(t); ok {
+		out := c.convert(sub, path)
+		out.setExtension("nullable", true)
+		return out
+	}
+	if intOrStringOneOf(t) {
+		out := &Type{
+			AnyOf:       []*Type{{Type: "integer"}, {Type: "string"}},
+			Title:       t.Title,
+			Description: t.Description,
+		}
+		out.setExtension("x-kubernetes-int-or-string", true)
+		return out
+	}
+	if t.Type == "" && len(t.OneOf) == 0 && len(t.AnyOf) == 0 && len(t.AllOf) == 0 && t.Not == nil {
+		out := &Type{
+			Title:       t.Title,
+			Description: t.Description,
+		}
+		out.setExtension("x-kubernetes-preserve-unknown-fields", true)
+		return out
+	}
+
+	out := *t
+	out.Version = ""
+	out.Definitions = nil
+	out.Examples = nil
+	out.PatternProperties = nil
+	out.AdditionalProperties = nil
+	out.Extras = nil
+	for k, v := range t.Extras {
+		out.setExtension(k, v)
+	}
+
+	if t.Media != nil {
+		out.Media = nil
+		if t.Media.BinaryEncoding == "base64" {
+			out.Format = "byte"
+		}
+	}
+	if len(t.Dependencies) > 0 {
+		c.problem(path, "dependencies are not structural")
+	}
+	if t.AdditionalItems != nil {
+		c.problem(path, "additionalItems is not structural")
+	}
+
+	if t.Items != nil {
+		out.Items = c.convert(t.Items, path+"/items")
+	}
+	if t.Properties != nil {
+		out.Properties = orderedmap.New()
+		for _, key := range t.Properties.Keys() {
+			v, _ := t.Properties.Get(key)
+			p, ok := v.(*Type)
+			if !ok {
+				c.problem(path+"/properties/"+pointerEscape(key), "property is not a schema")
+				continue
+			}
+			out.Properties.Set(key, c.convert(p, path+"/properties/"+pointerEscape(key)))
+		}
+	}
+
+	switch len(t.PatternProperties) {
+	case 0:
+		if string(t.AdditionalProperties) == "true" {
+			out.setExtension("x-kubernetes-preserve-unknown-fields", true)
+		}
+	case 1:
+		if t.Properties != nil && len(t.Properties.Keys()) > 0 {
+			c.problem(path, "properties and patternProperties cannot be combined")
+		}
+		for key, sub := range t.PatternProperties {
+			b, err := json.Marshal(c.convert(sub, path+"/patternProperties/"+pointerEscape(key)))
+			if err != nil {
+				c.problem(path, err.Error())
+			}
+			out.AdditionalProperties = b
+		}
+	default:
+		c.problem(path, "multiple patternProperties are not structural")
+	}
+
+	out.OneOf = c.junctions(t.OneOf, path+"/oneOf")
+	out.AnyOf = c.junctions(t.AnyOf, path+"/anyOf")
+	out.AllOf = c.junctions(t.AllOf, path+"/allOf")
+	if t.Not != nil {
+		out.Not = c.junction(t.Not, path+"/not")
+	}
+
+	if out.Type == "" {
+		c.problem(path, "schema has no type")
+	}
+	return &out
+}
+
+func (c *crd) junctions(subs []*Type, path string) []*Type {
+	var out []*Type
+	for i, sub := range subs {
+		out = append(out, c.junction(sub, path+"/"+strconv.Itoa(i)))
+	}
+	return out
+}
+
+// junction converts a schema nested in oneOf, anyOf, allOf or not, where only
+// value validations are allowed.
+func (c *crd) junction(t *Type, path string) *Type {
+	if t.Ref != "" || t.Type != "" || t.Properties != nil || t.Items != nil ||
+		len(t.PatternProperties) > 0 || t.AdditionalProperties != nil ||
+		t.Description != "" || t.Default != nil {
+		c.problem(path, "logical junctors may only contain value validations")
+		return t
+	}
+	out := *t
+	out.Version = ""
+	out.Title = ""
+	return &out
+}
+
+func (t *Type) setExtension(key string, value interface{}) {
+	if t.Extras == nil {
+		t.Extras = map[string]interface{}{}
+	}
+	t.Extras[key] = value
+}
+
+// nullableOneOf returns the non-null alternative of a {oneOf: [T, null]} schema.
+func nullableOneOf(t *Type) (*Type, bool) {
+	if len(t.OneOf) != 2 || t.Type != "" {
+		return nil, false
+	}
+	switch {
+	case t.OneOf[1].Type == "null":
+		return t.OneOf[0], true
+	case t.OneOf[0].Type == "null":
+		return t.OneOf[1], true
+	}
+	return nil, false
+}
+
+// intOrStringOneOf reports whether t accepts exactly a string or an integer.
+func intOrStringOneOf(t *Type) bool {
+	if len(t.OneOf) != 2 || t.Type != "" {
+		return false
+	}
+	types := map[string]bool{}
+	for _, sub := range t.OneOf {
+		types[sub.Type] = true
+	}
+	return types["string"] && types["integer"]
+}
@@ -0,0 +1,55 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type WidgetSpec struct {
+	Replicas int               `json:"replicas" jsonschema:"minimum=1"`
+	Port     ProtoEnum         `json:"port"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Config   json.RawMessage   `json:"config,omitempty"`
+	Owner    string            `json:"owner" jsonschema:"nullable"`
+	Data     []byte            `json:"data,omitempty"`
+	Backends []WidgetBackend   `json:"backends"`
+}
+
+type WidgetBackend struct {
+	Host string `json:"host,omitempty" jsonschema:"oneof_required=byHost"`
+	IP   string `json:"ip,omitempty" jsonschema:"oneof_required=byIP"`
+}
+
+type Widget struct {
+	Kind string     `json:"kind"`
+	Spec WidgetSpec `json:"spec"`
+}
+
+type NonStructural struct {
+	Value interface{}    `json:"value" jsonschema:"oneof_type=string;array"`
+	Self  *NonStructural `json:"self,omitempty"`
+}
+
+func TestCRDSchema(t *testing.T) {
+	actual, err := (&Reflector{}).ReflectCRD(&Widget{})
+	require.NoError(t, err)
+	actualJSON, err := json.MarshalIndent(actual, "", "  ")
+	require.NoError(t, err)
+	expectedJSON, err := ioutil.ReadFile("fixtures/crd.json")
+	require.NoError(t, err)
+	require.JSONEq(t, string(expectedJSON), string(actualJSON))
+}
+
+func TestCRDSchemaNonStructural(t *testing.T) {
+	_, err := (&Reflector{}).ReflectCRD(&NonStructural{})
+	require.Error(t, err)
+	require.Equal(t, []string{
+		"/properties/value/oneOf/0: logical junctors may only contain value validations",
+		"/properties/value/oneOf/1: logical junctors may only contain value validations",
+		"/properties/value: schema has no type",
+		"/properties/self: recursive reference to NonStructural cannot be inlined",
+	}, err.(*ConversionError).Problems)
+}
@@ -0,0 +1,233 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+)
+
+// ApplyDefaults fills the zero-valued fields of the struct pointed to by v
+// with their `default` tags, using the default Reflector.
+func ApplyDefaults(v interface{}) error {
+	r := &Reflector{}
+	return r.ApplyDefaults(v)
+}
+
+// ApplyDefaults fills the zero-valued fields of the struct pointed to by v
+// with the defaults from their `jsonschema` tags, naming fields as Reflect
+// does. Nested structs are filled too, through pointers, slices and maps of
+// pointers, and nil pointers to fields with a default are allocated.
+func (r *Reflector) ApplyDefaults(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.New("jsonschema: ApplyDefaults requires a non-nil pointer")
+	}
+	return r.applyDefaults(rv.Elem(), rv.Elem().Type().String())
+}
+
+func (r *Reflector) applyDefaults(v reflect.Value, path string) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		return r.applyDefaults(v.Elem(), path)
+	case reflect.Interface:
+		// Values held by interfaces are not addressable, so as with maps
+		// only pointers can be filled.
+		if v.IsNil() || v.Elem().Kind() != reflect.Ptr {
+			return nil
+		}
+		return r.applyDefaults(v.Elem(), path)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := r.applyDefaults(v.Index(i), path+"["+strconv.Itoa(i)+"]"); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		// Map values are not addressable, so only pointers can be filled.
+		for _, key := range v.MapKeys() {
+			if value := v.MapIndex(key); value.Kind() == reflect.Ptr {
+				if err := r.applyDefaults(value, fmt.Sprintf("%s[%v]", path, key)); err != nil {
+					return err
+				}
+			}
+		}
+	case reflect.Struct:
+		return r.applyStructDefaults(v, path)
+	}
+	return nil
+}
+
+func (r *Reflector) applyStructDefaults(v reflect.Value, path string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		fv := v.Field(i)
+		name, shouldEmbed, _, _ := r.reflectFieldName(f)
+		if name == "" {
+			if shouldEmbed {
+				if err := r.applyDefaults(fv, path); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		fieldPath := path + "." + f.Name
+		property := keywordsFromTags(f, name)
+		if property.Default != nil && fv.IsZero() && fv.CanSet() {
+			if err := setDefault(fv, property.Default); err != nil {
+				return fmt.Errorf("jsonschema: %s: %w", fieldPath, err)
+			}
+			continue
+		}
+		if err := r.applyDefaults(fv, fieldPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setDefault sets v to def, a default parsed from tags, converting it to the
+// kind of v.
+func setDefault(v reflect.Value, def interface{}) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		p := reflect.New(v.Type().Elem())
+		if err := setDefault(p.Elem(), def); err != nil {
+			return err
+		}
+		v.Set(p)
+		return nil
+	case reflect.Slice:
+		values, ok := def.([]interface{})
+		if !ok {
+			return fmt.Errorf("default %v is not a list", def)
+		}
+		s := reflect.MakeSlice(v.Type(), len(values), len(values))
+		for i, value := range values {
+			if err := setDefault(s.Index(i), value); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+		return nil
+	}
+
+	text := fmt.Sprint(def)
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(text)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(text, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(text, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(text, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("cannot apply default %v to %s", def, v.Type())
+	}
+	return nil
+}
+
+// ApplyDefaultsJSON inserts the defaults of s for every property missing from
+// the JSON document doc, following $refs, nested objects, maps and array
+// items, so that the result can be unmarshalled with defaults in place.
+//
+// Properties present in doc are left alone, even when null. The document is
+// re-encoded, so object keys are sorted and whitespace is not preserved.
+func ApplyDefaultsJSON(doc []byte, s *Schema) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	v = insertDefaults(s.Definitions, s.Type, v)
+	return json.Marshal(v)
+}
+
+func insertDefaults(definitions Definitions, t *Type, v interface{}) interface{} {
+	t = definitions.resolve(t)
+	if t == nil {
+		return v
+	}
+	if sub, ok := nullableOneOf(t); ok {
+		return insertDefaults(definitions, sub, v)
+	}
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if t.hasProperty(key) {
+				continue
+			}
+			for _, pattern := range sortedKeys(t.PatternProperties) {
+				if re, err := regexp.Compile(pattern); err == nil && re.MatchString(key) {
+					v[key] = insertDefaults(definitions, t.PatternProperties[pattern], value)
+					break
+				}
+			}
+		}
+		if t.Properties == nil {
+			return v
+		}
+		for _, key := range t.Properties.Keys() {
+			p, _ := t.Properties.Get(key)
+			property, ok := p.(*Type)
+			if !ok {
+				continue
+			}
+			if value, ok := v[key]; ok {
+				v[key] = insertDefaults(definitions, property, value)
+			} else if def := schemaDefault(definitions, property); def != nil {
+				v[key] = def
+			}
+		}
+	case []interface{}:
+		if t.Items == nil {
+			return v
+		}
+		for i, item := range v {
+			v[i] = insertDefaults(definitions, t.Items, item)
+		}
+	}
+	return v
+}
+
+// schemaDefault returns the default of t, looking through $refs and nullable
+// oneOfs.
+func schemaDefault(definitions Definitions, t *Type) interface{} {
+	t = definitions.resolve(t)
+	if t == nil {
+		return nil
+	}
+	if t.Default == nil {
+		if sub, ok := nullableOneOf(t); ok {
+			return schemaDefault(definitions, sub)
+		}
+	}
+	return t.Default
+}
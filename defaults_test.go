@@ -0,0 +1,118 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type DefaultsBase struct {
+	Region string `json:"region" jsonschema:"default=eu-west-1"`
+}
+
+type DefaultsServer struct {
+	DefaultsBase
+	Host     string                     `json:"host" jsonschema:"default=localhost"`
+	Port     int                        `json:"port" jsonschema:"default=8080"`
+	Ratio    float64                    `json:"ratio,omitempty" jsonschema:"default=2"`
+	Tags     []string                   `json:"tags,omitempty" jsonschema:"default=a,default=b"`
+	Timeout  *uint                      `json:"timeout,omitempty" jsonschema:"default=30"`
+	Backends []*DefaultsServer          `json:"backends,omitempty"`
+	Named    map[string]*DefaultsServer `json:"named,omitempty"`
+	Mode     string                     `json:"mode,omitempty" jsonschema:"nullable,default=auto"`
+	private  string                     `jsonschema:"default=secret"`
+}
+
+func TestApplyDefaults(t *testing.T) {
+	s := &DefaultsServer{
+		Host:     "example.com",
+		Backends: []*DefaultsServer{{Port: 9000}, nil},
+		Named:    map[string]*DefaultsServer{"a": {}},
+	}
+	require.NoError(t, ApplyDefaults(s))
+
+	timeout := uint(30)
+	require.Equal(t, "eu-west-1", s.Region)
+	require.Equal(t, "example.com", s.Host, "set fields are kept")
+	require.Equal(t, 8080, s.Port)
+	require.Equal(t, 2.0, s.Ratio)
+	require.Equal(t, []string{"a", "b"}, s.Tags)
+	require.Equal(t, &timeout, s.Timeout)
+	require.Equal(t, "auto", s.Mode)
+	require.Equal(t, "", s.private)
+	require.Equal(t, "localhost", s.Backends[0].Host)
+	require.Equal(t, 9000, s.Backends[0].Port)
+	require.Nil(t, s.Backends[1])
+	require.Equal(t, 8080, s.Named["a"].Port)
+
+	require.Error(t, ApplyDefaults(DefaultsServer{}))
+}
+
+func TestApplyDefaultsInterfaces(t *testing.T) {
+	type holder struct {
+		Value   interface{} `json:"value"`
+		Pointer interface{} `json:"pointer"`
+	}
+	v := &holder{Value: DefaultsBase{}, Pointer: &DefaultsBase{}}
+	require.NoError(t, ApplyDefaults(v))
+	require.Equal(t, DefaultsBase{}, v.Value, "values held by interfaces cannot be filled")
+	require.Equal(t, &DefaultsBase{Region: "eu-west-1"}, v.Pointer)
+}
+
+type defaultsHidden struct {
+	Zone string `json:"zone" jsonschema:"default=a"`
+}
+
+func TestApplyDefaultsUnexportedEmbedded(t *testing.T) {
+	type holder struct {
+		defaultsHidden
+		Host string `json:"host" jsonschema:"default=localhost"`
+	}
+	v := &holder{}
+	require.NotPanics(t, func() { require.NoError(t, ApplyDefaults(v)) })
+	require.Equal(t, "localhost", v.Host)
+	require.Equal(t, "a", v.Zone)
+}
+
+func TestApplyDefaultsInvalid(t *testing.T) {
+	type invalid struct {
+		Port int `json:"port" jsonschema:"default=x"`
+	}
+	v := &invalid{}
+	// Non-numeric integer defaults are parsed as 0 by the tags, like Reflect.
+	require.NoError(t, ApplyDefaults(v))
+	require.Equal(t, 0, v.Port)
+
+	type unsupported struct {
+		Flags map[string]bool `json:"flags"`
+		Count []int           `json:"count" jsonschema:"default=1,default=x"`
+	}
+	err := ApplyDefaults(&unsupported{})
+	require.EqualError(t, err, `jsonschema: jsonschema.unsupported.Count: strconv.ParseInt: parsing "x": invalid syntax`)
+}
+
+func TestApplyDefaultsJSON(t *testing.T) {
+	schema := Reflect(&DefaultsServer{})
+	doc := []byte(`{"host": "example.com", "ratio": 1.25, "mode": null, "backends": [{"port": 9000}], "named": {"a": {}}}`)
+	out, err := ApplyDefaultsJSON(doc, schema)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"region": "eu-west-1",
+		"host": "example.com",
+		"port": 8080,
+		"ratio": 1.25,
+		"tags": ["a", "b"],
+		"timeout": 30,
+		"mode": null,
+		"backends": [{"region": "eu-west-1", "host": "localhost", "port": 9000, "ratio": 2, "tags": ["a", "b"], "timeout": 30, "mode": "auto"}],
+		"named": {"a": {"region": "eu-west-1", "host": "localhost", "port": 8080, "ratio": 2, "tags": ["a", "b"], "timeout": 30, "mode": "auto"}}
+	}`, string(out))
+
+	var s DefaultsServer
+	require.NoError(t, json.Unmarshal(out, &s))
+	require.Equal(t, 8080, s.Port)
+
+	_, err = ApplyDefaultsJSON([]byte(`{`), schema)
+	require.Error(t, err)
+}
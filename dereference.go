@@ -0,0 +1,76 @@
+package jsonschema
+
+import "sort"
+
+// DereferenceOption configures Dereference.
+type DereferenceOption func(*dereferencer)
+
+// DropUnusedDefinitions removes the definitions that are no longer referenced
+// once their references have been inlined.
+func DropUnusedDefinitions() DereferenceOption {
+	return func(d *dereferencer) { d.drop = true }
+}
+
+// Dereference returns a copy of s in which every "#/definitions/" $ref is
+// replaced by a copy of the definition it points to, as DoNotReference does at
+// reflection time.
+//
+// References that would inline a definition within itself are left in place
+// and the names of those recursive definitions are returned, sorted. Other
+// references, such as those to other documents, are also left untouched.
+func Dereference(s *Schema, opts ...DereferenceOption) (*Schema, []string) {
+	d := &dereferencer{defs: s.Definitions, cycles: map[string]bool{}}
+	for _, opt := range opts {
+		opt(d)
+	}
+	out := &Schema{Type: d.inline(s.Type, nil)}
+	if out.Type != nil && s.Type.Ref != "" {
+		// Keep the keywords that identify the document.
+		if s.Type.Version != "" {
+			out.Type.Version = s.Type.Version
+		}
+		if s.Type.ID != "" {
+			out.Type.ID = s.Type.ID
+		}
+	}
+	if len(s.Definitions) > 0 {
+		out.Definitions = Definitions{}
+		for name, def := range s.Definitions {
+			out.Definitions[name] = d.inline(def, []string{name})
+		}
+	}
+	if d.drop {
+		out.pruneDefinitions()
+	}
+
+	cycles := make([]string, 0, len(d.cycles))
+	for name := range d.cycles {
+		cycles = append(cycles, name)
+	}
+	sort.Strings(cycles)
+	return out, cycles
+}
+
+type dereferencer struct {
+	defs   Definitions
+	drop   bool
+	cycles map[string]bool
+}
+
+// inline returns a copy of t with references replaced by their targets.
+// stack holds the definitions being inlined around t.
+func (d *dereferencer) inline(t *Type, stack []string) *Type {
+	if t == nil {
+		return nil
+	}
+	if name, ok := definitionName(t.Ref); ok && d.defs[name] != nil {
+		if contains(stack, name) {
+			d.cycles[name] = true
+			return t.clone()
+		}
+		return d.inline(d.defs[name], append(stack[:len(stack):len(stack)], name))
+	}
+	c := t.clone()
+	c.mapChildren(func(child *Type) *Type { return d.inline(child, stack) })
+	return c
+}
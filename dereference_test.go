@@ -0,0 +1,98 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type DereferenceNode struct {
+	Name     string             `json:"name"`
+	Children []*DereferenceNode `json:"children,omitempty"`
+}
+
+func TestDereference(t *testing.T) {
+	s, cycles := Dereference(Reflect(&TestUser{}), DropUnusedDefinitions())
+	require.Empty(t, cycles)
+	require.Nil(t, s.Definitions)
+	require.Equal(t, Version, s.Version)
+	s.Version = ""
+	inlined := (&Reflector{DoNotReference: true}).Reflect(&TestUser{})
+	inlined.Definitions = nil
+	expected, err := json.Marshal(inlined)
+	require.NoError(t, err)
+	actual, err := json.Marshal(s)
+	require.NoError(t, err)
+	require.JSONEq(t, string(expected), string(actual))
+}
+
+func TestDereferenceCycles(t *testing.T) {
+	original := Reflect(&DereferenceNode{})
+	before, err := json.Marshal(original)
+	require.NoError(t, err)
+
+	s, cycles := Dereference(original, DropUnusedDefinitions())
+	require.Equal(t, []string{"DereferenceNode"}, cycles)
+	b, err := json.Marshal(s)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"$schema": "http://json-schema.org/draft-04/schema#",
+		"type": "object",
+		"required": ["name"],
+		"properties": {
+			"name": {"type": "string"},
+			"children": {"type": "array", "items": {"$ref": "#/definitions/DereferenceNode"}}
+		},
+		"additionalProperties": false,
+		"definitions": {
+			"DereferenceNode": {
+				"type": "object",
+				"required": ["name"],
+				"properties": {
+					"name": {"type": "string"},
+					"children": {"type": "array", "items": {"$ref": "#/definitions/DereferenceNode"}}
+				},
+				"additionalProperties": false
+			}
+		}
+	}`, string(b))
+
+	after, err := json.Marshal(original)
+	require.NoError(t, err)
+	require.Equal(t, string(before), string(after), "the original schema is not modified")
+}
+
+func TestDereferenceKeepsDefinitions(t *testing.T) {
+	s := &Schema{
+		Type: &Type{AnyOf: []*Type{
+			{Ref: "#/definitions/A"},
+			{Ref: "other.json#/definitions/B"},
+			{Ref: "#/definitions/Missing"},
+		}},
+		Definitions: Definitions{
+			"A":      {Ref: "#/definitions/B"},
+			"B":      {Type: "string"},
+			"Unused": {Type: "integer"},
+		},
+	}
+	out, cycles := Dereference(s)
+	require.Empty(t, cycles)
+	b, err := json.Marshal(out)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"anyOf": [
+			{"type": "string"},
+			{"$ref": "other.json#/definitions/B"},
+			{"$ref": "#/definitions/Missing"}
+		],
+		"definitions": {
+			"A": {"type": "string"},
+			"B": {"type": "string"},
+			"Unused": {"type": "integer"}
+		}
+	}`, string(b))
+
+	out, _ = Dereference(s, DropUnusedDefinitions())
+	require.Nil(t, out.Definitions)
+}
@@ -0,0 +1,334 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// WriteMarkdown renders s as Markdown reference documentation.
+//
+// The root type and every definition get their own section, with a table of
+// properties in declaration order. References to other definitions are
+// rendered as links to their section.
+func WriteMarkdown(w io.Writer, s *Schema) error {
+	b := &strings.Builder{}
+	for i, section := range docSections(s) {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		// Headings get explicit anchors, since renderers such as GitHub's
+		// derive their own ids from the heading text differently.
+		fmt.Fprintf(b, "<a id=\"%s\"></a>\n\n## %s\n\n", section.Anchor, markdownEscape(section.Name))
+		if section.Title != "" {
+			fmt.Fprintf(b, "**%s**\n\n", markdownEscape(section.Title))
+		}
+		if section.Description != "" {
+			fmt.Fprintf(b, "%s\n\n", markdownEscape(section.Description))
+		}
+		fmt.Fprintf(b, "Type: %s\n", markdownLinks(section.Type))
+		for _, note := range section.Notes {
+			fmt.Fprintf(b, "\n%s: `%s`\n", note.Label, note.Value)
+		}
+		if len(section.Properties) == 0 {
+			continue
+		}
+		b.WriteString("\n| Property | Type | Required | Description |\n")
+		b.WriteString("| --- | --- | --- | --- |\n")
+		for _, p := range section.Properties {
+			required := "optional"
+			if p.Required {
+				required = "required"
+			}
+			description := []string{}
+			if p.Title != "" {
+				description = append(description, "**"+markdownEscape(p.Title)+"**")
+			}
+			if p.Description != "" {
+				description = append(description, markdownEscape(p.Description))
+			}
+			for _, note := range p.Notes {
+				description = append(description, note.Label+": `"+markdownCode(note.Value)+"`")
+			}
+			fmt.Fprintf(b, "| `%s` | %s | %s | %s |\n",
+				markdownCode(p.Name), markdownLinks(p.Type), required, strings.Join(description, "<br>"))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteHTML renders s as a static HTML page, with the same sections as
+// WriteMarkdown.
+func WriteHTML(w io.Writer, s *Schema) error {
+	sections := docSections(s)
+	title := "Schema"
+	if len(sections) > 0 {
+		title = sections[0].Name
+	}
+	return htmlDocTemplate.Execute(w, struct {
+		Title    string
+		Sections []*docSection
+	}{title, sections})
+}
+
+var htmlDocTemplate = template.Must(template.New("docs").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+</head>
+<body>
+{{- range .Sections}}
+<section id="{{.Anchor}}">
+<h2>{{.Name}}</h2>
+{{- if .Title}}
+<p><strong>{{.Title}}</strong></p>
+{{- end}}
+{{- if .Description}}
+<p>{{.Description}}</p>
+{{- end}}
+<p>Type: {{template "links" .Type}}</p>
+{{- range .Notes}}
+<p>{{.Label}}: <code>{{.Value}}</code></p>
+{{- end}}
+{{- if .Properties}}
+<table>
+<thead><tr><th>Property</th><th>Type</th><th>Required</th><th>Description</th></tr></thead>
+<tbody>
+{{- range .Properties}}
+<tr><td><code>{{.Name}}</code></td><td>{{template "links" .Type}}</td><td>{{if .Required}}required{{else}}optional{{end}}</td><td>{{template "details" .}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- end}}
+</section>
+{{- end}}
+</body>
+</html>
+{{define "details"}}{{$p := .}}{{if .Title}}<strong>{{.Title}}</strong>{{end}}{{if and .Title .Description}}<br>{{end}}{{.Description}}{{range $i, $n := .Notes}}{{if or $i $p.Title $p.Description}}<br>{{end}}{{.Label}}: <code>{{.Value}}</code>{{end}}{{end}}
+{{define "links"}}{{range .}}{{if .Anchor}}<a href="#{{.Anchor}}">{{.Text}}</a>{{else}}{{.Text}}{{end}}{{end}}{{end}}`))
+
+// docLink is a fragment of text, optionally linking to another section.
+type docLink struct {
+	Text   string
+	Anchor string
+}
+
+// docNote is a labelled JSON value, such as a default or the allowed values.
+type docNote struct {
+	Label string
+	Value string
+}
+
+type docSection struct {
+	Name        string
+	Anchor      string
+	Title       string
+	Description string
+	Type        []docLink
+	Notes       []docNote
+	Properties  []*docProperty
+}
+
+type docProperty struct {
+	Name        string
+	Required    bool
+	Type        []docLink
+	Title       string
+	Description string
+	Notes       []docNote
+}
+
+// docSections returns one section for the root type, followed by one for
+// every other definition in name order.
+func docSections(s *Schema) []*docSection {
+	var names []string
+	var types []*Type
+	root := ""
+	if name, ok := definitionName(s.Ref); ok && s.Definitions[name] != nil {
+		root = name
+		names, types = append(names, name), append(types, s.Definitions[name])
+	} else if s.Type != nil {
+		name := s.Title
+		if name == "" {
+			name = "Root"
+		}
+		names, types = append(names, name), append(types, s.Type)
+	}
+	for _, name := range sortedKeys(s.Definitions) {
+		if name != root {
+			names, types = append(names, name), append(types, s.Definitions[name])
+		}
+	}
+
+	anchors := newDocAnchors(names)
+	sections := make([]*docSection, len(names))
+	for i, name := range names {
+		sections[i] = newDocSection(name, types[i], anchors)
+	}
+	return sections
+}
+
+// docAnchors maps section names to their anchors.
+type docAnchors map[string]string
+
+// newDocAnchors gives each of names a distinct anchor, numbering those that
+// would otherwise be the same in order, such as "a-b" and "a-b-2" for "a.B"
+// and "a-B".
+func newDocAnchors(names []string) docAnchors {
+	anchors := docAnchors{}
+	used := map[string]bool{}
+	for _, name := range names {
+		anchor := docAnchor(name)
+		unique := anchor
+		for i := 2; used[unique]; i++ {
+			unique = fmt.Sprintf("%s-%d", anchor, i)
+		}
+		used[unique] = true
+		anchors[name] = unique
+	}
+	return anchors
+}
+
+func newDocSection(name string, t *Type, anchors docAnchors) *docSection {
+	section := &docSection{
+		Name:        name,
+		Anchor:      anchors[name],
+		Title:       t.Title,
+		Description: t.Description,
+		Type:        anchors.typeLinks(t),
+		Notes:       docNotes(t),
+	}
+	if t.Properties == nil {
+		return section
+	}
+	for _, key := range t.Properties.Keys() {
+		v, _ := t.Properties.Get(key)
+		p, ok := v.(*Type)
+		if !ok {
+			continue
+		}
+		section.Properties = append(section.Properties, &docProperty{
+			Name:        key,
+			Required:    contains(t.Required, key),
+			Type:        anchors.typeLinks(p),
+			Title:       p.Title,
+			Description: p.Description,
+			Notes:       docNotes(p),
+		})
+	}
+	return section
+}
+
+func docNotes(t *Type) []docNote {
+	var notes []docNote
+	if t.Default != nil {
+		notes = append(notes, docNote{"Default", docJSON(t.Default)})
+	}
+	if len(t.Enum) > 0 {
+		notes = append(notes, docNote{"Allowed values", docJSONList(t.Enum)})
+	}
+	if len(t.Examples) > 0 {
+		notes = append(notes, docNote{"Examples", docJSONList(t.Examples)})
+	}
+	return notes
+}
+
+// typeLinks describes the type of t in a few words.
+func (anchors docAnchors) typeLinks(t *Type) []docLink {
+	if name, ok := definitionName(t.Ref); ok {
+		return []docLink{{Text: name, Anchor: anchors[name]}}
+	}
+	if t.Ref != "" {
+		return []docLink{{Text: t.Ref}}
+	}
+	alternatives := t.OneOf
+	if len(alternatives) == 0 {
+		alternatives = t.AnyOf
+	}
+	if len(alternatives) > 0 && t.Type == "" {
+		var out []docLink
+		for i, sub := range alternatives {
+			if i > 0 {
+				out = append(out, docLink{Text: " or "})
+			}
+			out = append(out, anchors.typeLinks(sub)...)
+		}
+		return out
+	}
+	switch t.Type {
+	case "array":
+		if t.Items == nil {
+			return []docLink{{Text: "array"}}
+		}
+		return append([]docLink{{Text: "array of "}}, anchors.typeLinks(t.Items)...)
+	case "object":
+		if len(t.PatternProperties) == 1 {
+			for _, sub := range t.PatternProperties {
+				return append([]docLink{{Text: "map of "}}, anchors.typeLinks(sub)...)
+			}
+		}
+		return []docLink{{Text: "object"}}
+	case "":
+		return []docLink{{Text: "any"}}
+	}
+	if t.Format != "" {
+		return []docLink{{Text: t.Type + " (" + t.Format + ")"}}
+	}
+	return []docLink{{Text: t.Type}}
+}
+
+// docAnchor derives a stable HTML id from a definition name.
+func docAnchor(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return '-'
+	}, name)
+}
+
+func docJSON(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+func docJSONList(values []interface{}) string {
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = docJSON(v)
+	}
+	return strings.Join(out, ", ")
+}
+
+func markdownLinks(links []docLink) string {
+	b := &strings.Builder{}
+	for _, link := range links {
+		if link.Anchor != "" {
+			fmt.Fprintf(b, "[%s](#%s)", markdownEscape(link.Text), link.Anchor)
+		} else {
+			b.WriteString(markdownEscape(link.Text))
+		}
+	}
+	return b.String()
+}
+
+// markdownEscape escapes s for use as Markdown text on a single line, such as
+// in a heading or a table cell.
+func markdownEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "|", `\|`, "<", `\<`, "`", "\\`",
+		"[", `\[`, "]", `\]`, "\n", " ", "\r", "").Replace(s)
+}
+
+// markdownCode escapes s for use in a code span within a table cell, where
+// only pipes need escaping.
+func markdownCode(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ", "\r", "").Replace(s)
+}
@@ -0,0 +1,80 @@
+package jsonschema
+
+import (
+	"bytes"
+	"io/ioutil"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/iancoleman/orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+type DocsConfig struct {
+	Name     string            `json:"name" jsonschema:"description=name of the service,default=api,example=api,example=worker"`
+	Level    string            `json:"level,omitempty" jsonschema:"enum=debug,enum=info,enum=warn"`
+	Listen   []DocsListener    `json:"listen,omitempty"`
+	Labels   map[string]string `json:"labels,omitempty" jsonschema_description:"labels | attached to metrics"`
+	Owner    string            `json:"owner" jsonschema:"nullable"`
+	Deadline time.Time         `json:"deadline"`
+}
+
+type DocsListener struct {
+	Address string `json:"address" jsonschema:"title=Bind address"`
+	Port    int    `json:"port,omitempty" jsonschema:"default=8080"`
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	b := &bytes.Buffer{}
+	require.NoError(t, WriteMarkdown(b, Reflect(&DocsConfig{})))
+	expected, err := ioutil.ReadFile("fixtures/docs.md")
+	require.NoError(t, err)
+	require.Equal(t, string(expected), b.String())
+}
+
+func TestWriteHTML(t *testing.T) {
+	b := &bytes.Buffer{}
+	require.NoError(t, WriteHTML(b, Reflect(&DocsConfig{})))
+	expected, err := ioutil.ReadFile("fixtures/docs.html")
+	require.NoError(t, err)
+	require.Equal(t, string(expected), b.String())
+}
+
+func TestWriteMarkdownAnchors(t *testing.T) {
+	b := &bytes.Buffer{}
+	schema := (&Reflector{TypeNamer: func(t reflect.Type) string { return "api." + t.Name() }}).Reflect(&DocsConfig{})
+	require.NoError(t, WriteMarkdown(b, schema))
+	require.Contains(t, b.String(), "<a id=\"api-docslistener\"></a>\n\n## api.DocsListener\n")
+	require.Contains(t, b.String(), "[api.DocsListener](#api-docslistener)")
+
+	schema = &Schema{
+		Type: &Type{Ref: "#/definitions/a.B"},
+		Definitions: Definitions{
+			"a.B": {Type: "object", Properties: docsProperties("other", &Type{Ref: "#/definitions/a-B"})},
+			"a-B": {Type: "string", Description: "a *b* | <c>"},
+		},
+	}
+	b.Reset()
+	require.NoError(t, WriteMarkdown(b, schema))
+	require.Contains(t, b.String(), "<a id=\"a-b\"></a>\n\n## a.B\n")
+	require.Contains(t, b.String(), "<a id=\"a-b-2\"></a>\n\n## a-B\n\na \\*b\\* \\| \\<c>\n")
+	require.Contains(t, b.String(), "| `other` | [a-B](#a-b-2) |")
+
+	b.Reset()
+	require.NoError(t, WriteHTML(b, schema))
+	require.Contains(t, b.String(), `<section id="a-b-2">`)
+	require.Contains(t, b.String(), `<a href="#a-b-2">a-B</a>`)
+	require.Contains(t, b.String(), "<p>a *b* | &lt;c&gt;</p>")
+}
+
+func TestMarkdownEscape(t *testing.T) {
+	require.Equal(t, `snake\_case \*bold\* a\|b \<br> \[x\] c:\\`, markdownEscape("snake_case *bold* a|b <br> [x] c:\\"))
+	require.Equal(t, `snake_case a\|b`, markdownCode("snake_case a|b"))
+}
+
+func docsProperties(key string, t *Type) *orderedmap.OrderedMap {
+	properties := orderedmap.New()
+	properties.Set(key, t)
+	return properties
+}
@@ -0,0 +1,13 @@
+CREATE TABLE "rows" (
+  "id" BIGINT NOT NULL,
+  "name" TEXT NOT NULL,
+  "status" TEXT CHECK ("status" IN ('active', 'it''s off')),
+  "score" DOUBLE PRECISION,
+  "active" BOOLEAN NOT NULL,
+  "created_at" TIMESTAMPTZ NOT NULL,
+  "avatar" BYTEA,
+  "tags" JSONB NOT NULL,
+  "labels" JSONB,
+  "address" JSONB NOT NULL,
+  "history" JSONB
+);
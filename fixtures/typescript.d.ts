@@ -0,0 +1,16 @@
+export interface TSOrder {
+  /** unique order id */
+  id: string;
+  status: "pending" | "shipped";
+  items: TSOrderItem[];
+  notes?: string[] | null;
+  metadata?: Record<string, string>;
+  counts?: Record<string, number>;
+  extra?: string | number;
+  "x-shipping"?: TSOrderItem;
+}
+
+export interface TSOrderItem {
+  sku: string;
+  quantity?: number;
+}
@@ -0,0 +1,553 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"regexp"
+	"regexp/syntax"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// maxGenerateDepth bounds how deeply Generate follows $refs before it only
+// generates what is required to satisfy the schema.
+const maxGenerateDepth = 8
+
+// Generate produces a JSON value satisfying schema, using src for randomness.
+// The same schema and seed always produce the same value.
+//
+// Values respect type, enum, pattern, format, length, range, item and
+// required constraints, pick one alternative of oneOf and anyOf, and follow
+// $refs into the schema's definitions. Examples are used as values when
+// present and valid. allOf, not and dependencies are not supported, and
+// constraints no value can satisfy are reported as errors.
+//
+// Objects are returned as map[string]interface{}, arrays as []interface{}
+// and numbers as int64 or float64, ready to be marshalled to JSON.
+func Generate(schema *Schema, src rand.Source) (interface{}, error) {
+	g := &generator{
+		rand:        rand.New(src),
+		definitions: schema.Definitions,
+		patterns:    map[string]*regexp.Regexp{},
+	}
+	g.validator = &validator{
+		loader:   MetaSchemas,
+		docs:     map[string]*Schema{},
+		patterns: g.patterns,
+		schema:   schema,
+		base:     schemaID(schema.Type),
+	}
+	return g.generate(schema.Type, "", 0)
+}
+
+type generator struct {
+	rand        *rand.Rand
+	definitions Definitions
+	patterns    map[string]*regexp.Regexp
+	validator   *validator
+}
+
+func (g *generator) generate(t *Type, path string, depth int) (interface{}, error) {
+	if t.Ref != "" {
+		name, ok := definitionName(t.Ref)
+		def := g.definitions[name]
+		if !ok || def == nil {
+			return nil, fmt.Errorf("%s: unresolvable $ref %s", generatePath(path), t.Ref)
+		}
+		if depth > 4*maxGenerateDepth {
+			return nil, fmt.Errorf("%s: schema requires unbounded recursion through %s", generatePath(path), name)
+		}
+		return g.generate(def, path, depth+1)
+	}
+	switch {
+	case len(t.AllOf) > 0:
+		return nil, fmt.Errorf("%s: allOf is not supported", generatePath(path))
+	case t.Not != nil:
+		return nil, fmt.Errorf("%s: not is not supported", generatePath(path))
+	case len(t.Dependencies) > 0:
+		return nil, fmt.Errorf("%s: dependencies are not supported", generatePath(path))
+	}
+
+	if len(t.Enum) > 0 {
+		return t.Enum[g.rand.Intn(len(t.Enum))], nil
+	}
+	if examples := g.validExamples(t); len(examples) > 0 {
+		return examples[g.rand.Intn(len(examples))], nil
+	}
+	if t.Type == "" && len(t.OneOf) > 0 {
+		return g.alternative(t.OneOf, path+"/oneOf", depth)
+	}
+	if t.Type == "" && len(t.AnyOf) > 0 {
+		return g.alternative(t.AnyOf, path+"/anyOf", depth)
+	}
+
+	switch t.Type {
+	case "string":
+		return g.string(t, path)
+	case "integer":
+		return g.integer(t, path)
+	case "number":
+		return g.number(t, path)
+	case "boolean":
+		return g.rand.Intn(2) == 1, nil
+	case "null":
+		return nil, nil
+	case "array":
+		return g.array(t, path, depth)
+	case "object":
+		return g.object(t, path, depth)
+	case "":
+		// Free-form values accept anything.
+		return g.word(1, 8), nil
+	}
+	return nil, fmt.Errorf("%s: unsupported type %s", generatePath(path), t.Type)
+}
+
+// validExamples returns the examples of t that satisfy it.
+func (g *generator) validExamples(t *Type) []interface{} {
+	var examples []interface{}
+	for _, example := range t.Examples {
+		b, err := json.Marshal(example)
+		if err != nil {
+			continue
+		}
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.UseNumber()
+		var v interface{}
+		if dec.Decode(&v) == nil && len(g.validator.validate(t, v, "")) == 0 {
+			examples = append(examples, example)
+		}
+	}
+	return examples
+}
+
+func (g *generator) alternative(alternatives []*Type, path string, depth int) (interface{}, error) {
+	i := g.rand.Intn(len(alternatives))
+	// Past the depth limit, prefer null so recursion can end.
+	if depth > maxGenerateDepth {
+		for j, alt := range alternatives {
+			if alt.Type == "null" {
+				i = j
+			}
+		}
+	}
+	return g.generate(alternatives[i], path+"/"+strconv.Itoa(i), depth)
+}
+
+func (g *generator) string(t *Type, path string) (interface{}, error) {
+	if t.Media != nil && t.Media.BinaryEncoding == "base64" {
+		b := make([]byte, g.between(t.MinLength, t.MaxLength, 4, 16))
+		g.rand.Read(b)
+		return base64.StdEncoding.EncodeToString(b), nil
+	}
+	if s, ok := g.format(t.Format); ok {
+		return s, nil
+	}
+	if t.Pattern != "" {
+		re, ok := g.patterns[t.Pattern]
+		if !ok {
+			var err error
+			re, err = regexp.Compile(t.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("%s: invalid pattern: %w", generatePath(path), err)
+			}
+			g.patterns[t.Pattern] = re
+		}
+		for attempt := 0; attempt < 100; attempt++ {
+			s, err := generateFromPattern(g.rand, t.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", generatePath(path), err)
+			}
+			n := len([]rune(s))
+			if re.MatchString(s) && n >= t.MinLength && (t.MaxLength == 0 || n <= t.MaxLength) {
+				return s, nil
+			}
+		}
+		return nil, fmt.Errorf("%s: could not generate a string matching %q within the length limits", generatePath(path), t.Pattern)
+	}
+	return g.word(t.MinLength, t.MaxLength), nil
+}
+
+// format generates a value for the formats known to the Reflector.
+func (g *generator) format(format string) (string, bool) {
+	switch format {
+	case "date-time":
+		return g.time().Format(time.RFC3339), true
+	case "date":
+		return g.time().Format("2006-01-02"), true
+	case "email":
+		return g.word(3, 8) + "@" + g.word(3, 8) + ".example.com", true
+	case "hostname":
+		return g.word(3, 8) + ".example.com", true
+	case "ipv4":
+		return fmt.Sprintf("%d.%d.%d.%d", g.rand.Intn(256), g.rand.Intn(256), g.rand.Intn(256), g.rand.Intn(256)), true
+	case "ipv6":
+		parts := make([]string, 8)
+		for i := range parts {
+			parts[i] = strconv.FormatInt(int64(g.rand.Intn(1<<16)), 16)
+		}
+		return strings.Join(parts, ":"), true
+	case "uri":
+		return "https://" + g.word(3, 8) + ".example.com/" + g.word(1, 8), true
+	case "uuid":
+		b := make([]byte, 16)
+		g.rand.Read(b)
+		b[6] = b[6]&0x0f | 0x40
+		b[8] = b[8]&0x3f | 0x80
+		return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), true
+	}
+	return "", false
+}
+
+func (g *generator) time() time.Time {
+	start := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	return start.Add(time.Duration(g.rand.Int63n(int64(30 * 365 * 24 * time.Hour)))).Truncate(time.Second)
+}
+
+// word generates a lower case word with a length between min and max, where
+// a zero max means unbounded.
+func (g *generator) word(min, max int) string {
+	n := g.between(min, max, 3, 10)
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte('a' + g.rand.Intn(26))
+	}
+	return string(b)
+}
+
+// between picks a length respecting min and max, where a zero max means
+// unbounded, defaulting to the range [lo, hi].
+func (g *generator) between(min, max, lo, hi int) int {
+	if lo < min {
+		lo = min
+	}
+	if hi < lo {
+		hi = lo + 3
+	}
+	if max > 0 && hi > max {
+		hi = max
+	}
+	if lo > hi {
+		lo = min
+	}
+	if hi <= lo {
+		return lo
+	}
+	return lo + g.rand.Intn(hi-lo+1)
+}
+
+// bounds returns the inclusive range of t. A zero minimum or maximum is
+// indistinguishable from an absent one, so they default to a range of 1000
+// around the other bound.
+func bounds(t *Type) (float64, float64) {
+	lo, hi := 0.0, 1000.0
+	switch {
+	case t.Minimum != 0 && t.Maximum != 0:
+		lo, hi = float64(t.Minimum), float64(t.Maximum)
+	case t.Minimum != 0:
+		lo, hi = float64(t.Minimum), float64(t.Minimum)+1000
+	case t.Maximum != 0:
+		lo, hi = math.Min(0, float64(t.Maximum)-1000), float64(t.Maximum)
+	}
+	return lo, hi
+}
+
+// integerBounds returns the inclusive integer range of t.
+func integerBounds(t *Type) (int64, int64) {
+	lo, hi := bounds(t)
+	min, max := int64(math.Ceil(lo)), int64(math.Floor(hi))
+	if t.ExclusiveMinimum && float64(min) == lo {
+		min++
+	}
+	if t.ExclusiveMaximum && float64(max) == hi {
+		max--
+	}
+	return min, max
+}
+
+func (g *generator) integer(t *Type, path string) (interface{}, error) {
+	min, max := integerBounds(t)
+	if t.MultipleOf > 0 {
+		step := int64(t.MultipleOf)
+		first := int64(math.Ceil(float64(min)/float64(step))) * step
+		if first > max {
+			return nil, fmt.Errorf("%s: no multiple of %d lies within the minimum and maximum", generatePath(path), step)
+		}
+		return first + step*g.rand.Int63n((max-first)/step+1), nil
+	}
+	if max < min {
+		return nil, fmt.Errorf("%s: no integer lies within the minimum and maximum", generatePath(path))
+	}
+	return min + g.rand.Int63n(max-min+1), nil
+}
+
+func (g *generator) number(t *Type, path string) (interface{}, error) {
+	if t.MultipleOf > 0 {
+		v, err := g.integer(t, path)
+		if err != nil {
+			return nil, err
+		}
+		return float64(v.(int64)), nil
+	}
+	lo, hi := bounds(t)
+	if lo > hi || lo == hi && (t.ExclusiveMinimum || t.ExclusiveMaximum) {
+		return nil, fmt.Errorf("%s: no number lies within the minimum and maximum", generatePath(path))
+	}
+	// Keep generated numbers short and readable.
+	v := math.Round((lo+g.rand.Float64()*(hi-lo))*100) / 100
+	if (t.ExclusiveMinimum && v <= lo) || (t.ExclusiveMaximum && v >= hi) {
+		v = (lo + hi) / 2
+	}
+	return v, nil
+}
+
+func (g *generator) array(t *Type, path string, depth int) (interface{}, error) {
+	min, max := t.MinItems, t.MaxItems
+	if depth > maxGenerateDepth {
+		max = min
+	}
+	n := g.between(min, max, 1, 3)
+	if depth > maxGenerateDepth {
+		n = min
+	}
+	items := []interface{}{}
+	if t.Items == nil {
+		for i := 0; i < n; i++ {
+			items = append(items, g.word(1, 8))
+		}
+		return items, nil
+	}
+	seen := map[string]bool{}
+	for attempt := 0; len(items) < n && attempt < 10*n+10; attempt++ {
+		item, err := g.generate(t.Items, path+"/items", depth+1)
+		if err != nil {
+			return nil, err
+		}
+		if t.UniqueItems {
+			b, _ := json.Marshal(item)
+			if seen[string(b)] {
+				continue
+			}
+			seen[string(b)] = true
+		}
+		items = append(items, item)
+	}
+	if len(items) < n {
+		return nil, fmt.Errorf("%s: could not generate %d unique items", generatePath(path), n)
+	}
+	return items, nil
+}
+
+func (g *generator) object(t *Type, path string, depth int) (interface{}, error) {
+	obj := map[string]interface{}{}
+
+	required := map[string]bool{}
+	for _, key := range t.Required {
+		required[key] = true
+	}
+	// Exactly one oneof_required group must be satisfied, so pick one and
+	// leave out a property of every other group.
+	excluded := map[string]bool{}
+	var groups []*Type
+	for _, sub := range t.OneOf {
+		if sub.Type == "" && len(sub.Required) > 0 {
+			groups = append(groups, sub)
+		}
+	}
+	if len(groups) > 0 {
+		chosen := groups[g.rand.Intn(len(groups))]
+		for _, key := range chosen.Required {
+			required[key] = true
+		}
+		for _, group := range groups {
+			if group == chosen {
+				continue
+			}
+			for _, key := range group.Required {
+				if !required[key] {
+					excluded[key] = true
+					break
+				}
+			}
+		}
+	}
+
+	if t.Properties != nil {
+		for _, key := range t.Properties.Keys() {
+			v, _ := t.Properties.Get(key)
+			p, ok := v.(*Type)
+			if !ok || excluded[key] {
+				continue
+			}
+			if !required[key] && (depth > maxGenerateDepth || g.rand.Intn(2) == 0) {
+				continue
+			}
+			value, err := g.generate(p, path+"/properties/"+pointerEscape(key), depth+1)
+			if err != nil {
+				return nil, err
+			}
+			obj[key] = value
+		}
+	}
+
+	for _, pattern := range sortedKeys(t.PatternProperties) {
+		n := g.rand.Intn(3)
+		if depth > maxGenerateDepth {
+			n = 0
+		}
+		for i := 0; i < n; i++ {
+			key := g.word(3, 8)
+			if pattern != ".*" {
+				var err error
+				key, err = generateFromPattern(g.rand, pattern)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", generatePath(path), err)
+				}
+			}
+			value, err := g.generate(t.PatternProperties[pattern], path+"/patternProperties/"+pointerEscape(pattern), depth+1)
+			if err != nil {
+				return nil, err
+			}
+			obj[key] = value
+		}
+	}
+
+	// Required names without a property are generated from the
+	// patternProperties they match or additionalProperties.
+	for _, key := range t.Required {
+		if _, ok := obj[key]; ok || t.hasProperty(key) {
+			continue
+		}
+		value, err := g.extraProperty(t, key, path, depth)
+		if err != nil {
+			return nil, err
+		}
+		obj[key] = value
+	}
+	return obj, nil
+}
+
+func (g *generator) extraProperty(t *Type, key, path string, depth int) (interface{}, error) {
+	for _, pattern := range sortedKeys(t.PatternProperties) {
+		if re, err := g.validator.pattern(pattern); err == nil && re.MatchString(key) {
+			return g.generate(t.PatternProperties[pattern], path+"/patternProperties/"+pointerEscape(pattern), depth+1)
+		}
+	}
+	switch raw := bytes.TrimSpace(t.AdditionalProperties); {
+	case string(raw) == "false":
+		return nil, fmt.Errorf("%s: required property %q is not allowed by additionalProperties", generatePath(path), key)
+	case len(raw) > 0 && raw[0] == '{':
+		additional := &Type{}
+		if err := json.Unmarshal(raw, additional); err != nil {
+			return nil, fmt.Errorf("%s: invalid additionalProperties: %w", generatePath(path), err)
+		}
+		return g.generate(additional, path+"/additionalProperties", depth+1)
+	}
+	return g.word(1, 8), nil
+}
+
+func generatePath(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return path
+}
+
+// generateFromPattern generates a string matching the regular expression
+// pattern. Unbounded repetitions are limited to a few occurrences.
+func generateFromPattern(r *rand.Rand, pattern string) (string, error) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+	b := &strings.Builder{}
+	generateRegexp(r, b, re.Simplify())
+	return b.String(), nil
+}
+
+func generateRegexp(r *rand.Rand, b *strings.Builder, re *syntax.Regexp) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		for _, c := range re.Rune {
+			b.WriteRune(c)
+		}
+	case syntax.OpCharClass:
+		b.WriteRune(charClassRune(r, re.Rune))
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		b.WriteRune(rune('a' + r.Intn(26)))
+	case syntax.OpCapture:
+		generateRegexp(r, b, re.Sub[0])
+	case syntax.OpStar:
+		repeatRegexp(r, b, re.Sub[0], 0, 3)
+	case syntax.OpPlus:
+		repeatRegexp(r, b, re.Sub[0], 1, 4)
+	case syntax.OpQuest:
+		repeatRegexp(r, b, re.Sub[0], 0, 1)
+	case syntax.OpRepeat:
+		max := re.Max
+		if max < 0 {
+			max = re.Min + 3
+		}
+		repeatRegexp(r, b, re.Sub[0], re.Min, max)
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			generateRegexp(r, b, sub)
+		}
+	case syntax.OpAlternate:
+		generateRegexp(r, b, re.Sub[r.Intn(len(re.Sub))])
+	}
+	// Anchors, word boundaries and empty matches generate nothing.
+}
+
+func repeatRegexp(r *rand.Rand, b *strings.Builder, re *syntax.Regexp, min, max int) {
+	n := min
+	if max > min {
+		n += r.Intn(max - min + 1)
+	}
+	for i := 0; i < n; i++ {
+		generateRegexp(r, b, re)
+	}
+}
+
+// charClassRune picks a rune from the ranges of a character class, preferring
+// printable ASCII so that negated classes produce readable output.
+func charClassRune(r *rand.Rand, ranges []rune) rune {
+	var printable []rune
+	for i := 0; i+1 < len(ranges); i += 2 {
+		lo, hi := ranges[i], ranges[i+1]
+		if lo < ' ' {
+			lo = ' '
+		}
+		if hi > '~' {
+			hi = '~'
+		}
+		if lo <= hi {
+			printable = append(printable, lo, hi)
+		}
+	}
+	if len(printable) > 0 {
+		ranges = printable
+	}
+	total := 0
+	for i := 0; i+1 < len(ranges); i += 2 {
+		total += int(ranges[i+1]-ranges[i]) + 1
+	}
+	if total == 0 {
+		return unicode.ReplacementChar
+	}
+	n := r.Intn(total)
+	for i := 0; i+1 < len(ranges); i += 2 {
+		size := int(ranges[i+1]-ranges[i]) + 1
+		if n < size {
+			return ranges[i] + rune(n)
+		}
+		n -= size
+	}
+	return ranges[0]
+}
@@ -0,0 +1,119 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"math/rand"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type GenerateTarget struct {
+	ID       int               `json:"id" jsonschema:"minimum=10,maximum=20"`
+	Code     string            `json:"code" jsonschema:"pattern=^[A-Z]{3}-[0-9]+$"`
+	Name     string            `json:"name" jsonschema:"minLength=2,maxLength=5"`
+	Email    string            `json:"email" jsonschema:"format=email"`
+	Color    string            `json:"color" jsonschema:"enum=red,enum=green"`
+	Tags     []string          `json:"tags" jsonschema:"minItems=2,maxItems=4,uniqueItems=true"`
+	Labels   map[string]string `json:"labels"`
+	Note     string            `json:"note" jsonschema:"nullable"`
+	Children []GenerateTarget  `json:"children,omitempty"`
+	Parent   *GenerateTarget   `json:"parent,omitempty"`
+}
+
+func TestGenerate(t *testing.T) {
+	schema := Reflect(&GenerateTarget{})
+	code := regexp.MustCompile(`^[A-Z]{3}-[0-9]+$`)
+	for seed := int64(0); seed < 50; seed++ {
+		v, err := Generate(schema, rand.NewSource(seed))
+		require.NoError(t, err)
+		again, err := Generate(schema, rand.NewSource(seed))
+		require.NoError(t, err)
+		require.Equal(t, v, again, "generation must be deterministic")
+
+		obj := v.(map[string]interface{})
+		require.Subset(t, keys(obj), []string{"id", "code", "name", "email", "color", "tags", "labels", "note"})
+		require.GreaterOrEqual(t, obj["id"], int64(10))
+		require.LessOrEqual(t, obj["id"], int64(20))
+		require.Regexp(t, code, obj["code"])
+		require.GreaterOrEqual(t, len(obj["name"].(string)), 2)
+		require.LessOrEqual(t, len(obj["name"].(string)), 5)
+		require.Contains(t, obj["email"], "@")
+		require.Contains(t, []interface{}{"red", "green"}, obj["color"])
+		tags := obj["tags"].([]interface{})
+		require.True(t, len(tags) >= 2 && len(tags) <= 4)
+		requireGeneratedValid(t, schema, v)
+	}
+}
+
+func TestGenerateOneOfRequired(t *testing.T) {
+	schema := (&Reflector{RequiredFromJSONSchemaTags: true}).Reflect(&RootOneOf{})
+	for seed := int64(0); seed < 20; seed++ {
+		v, err := Generate(schema, rand.NewSource(seed))
+		require.NoError(t, err)
+		requireGeneratedValid(t, schema, v)
+		obj := v.(map[string]interface{})
+		_, hasGroup1 := obj["field1"]
+		_, hasGroup2 := obj["field2"]
+		require.True(t, hasGroup1 != hasGroup2, "exactly one oneof_required group must be present: %v", obj)
+	}
+}
+
+func TestGenerateConstraints(t *testing.T) {
+	for _, tt := range []struct {
+		schema string
+		err    string
+	}{
+		{`{"type": "integer", "minimum": 1, "maximum": 3, "multipleOf": 5}`, "/: no multiple of 5 lies within the minimum and maximum"},
+		{`{"type": "integer", "minimum": 5, "maximum": 3}`, "/: no integer lies within the minimum and maximum"},
+		{`{"type": "number", "minimum": 3, "maximum": 3, "exclusiveMaximum": true}`, "/: no number lies within the minimum and maximum"},
+		{`{"type": "object", "required": ["id"], "additionalProperties": false}`, `/: required property "id" is not allowed by additionalProperties`},
+		{`{"type": "number", "minimum": 1, "maximum": 2, "exclusiveMinimum": true, "exclusiveMaximum": true}`, ""},
+		{`{"type": "object", "required": ["id", "x-a"], "patternProperties": {"^x-": {"type": "integer"}}}`, ""},
+		{`{"type": "object", "required": ["id"], "additionalProperties": {"type": "boolean"}}`, ""},
+		{`{"type": "string", "maxLength": 3, "examples": ["toolong", "ok"]}`, ""},
+	} {
+		schema := &Schema{}
+		require.NoError(t, json.Unmarshal([]byte(tt.schema), schema), tt.schema)
+		for seed := int64(0); seed < 20; seed++ {
+			v, err := Generate(schema, rand.NewSource(seed))
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err, tt.schema)
+				break
+			}
+			require.NoError(t, err, tt.schema)
+			requireGeneratedValid(t, schema, v)
+		}
+	}
+}
+
+// requireGeneratedValid checks that v, a generated value, satisfies schema.
+func requireGeneratedValid(t *testing.T, schema *Schema, v interface{}) {
+	t.Helper()
+	doc, err := json.Marshal(v)
+	require.NoError(t, err)
+	require.NoError(t, Validate(schema, doc), string(doc))
+}
+
+func TestGenerateFromPattern(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, pattern := range []string{`^[a-z]+@[a-z]+\.com$`, `(foo|bar)?\d{3}`, `[^0-9]{2}x*`, `^.{5}$`} {
+		re := regexp.MustCompile(pattern)
+		for i := 0; i < 20; i++ {
+			s, err := generateFromPattern(r, pattern)
+			require.NoError(t, err)
+			require.Regexp(t, re, s)
+		}
+	}
+	_, err := generateFromPattern(r, "(")
+	require.Error(t, err)
+}
+
+func keys(m map[string]interface{}) []string {
+	var out []string
+	for k := range m {
+		out = append(out, k)
+	}
+	return out
+}
@@ -0,0 +1,95 @@
+package jsonschema
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strconv"
+)
+
+// Problem is an RFC 7807 problem details object, as written by
+// ValidateRequest.
+type Problem struct {
+	Type   string          `json:"type"`
+	Title  string          `json:"title"`
+	Status int             `json:"status"`
+	Detail string          `json:"detail,omitempty"`
+	Errors []*ProblemError `json:"errors,omitempty"`
+}
+
+// ProblemError locates a single validation problem within a request body.
+type ProblemError struct {
+	Pointer string `json:"pointer"`
+	Keyword string `json:"keyword"`
+	Detail  string `json:"detail"`
+}
+
+type requestValueKey struct{}
+
+// ValidateRequest returns middleware that decodes request bodies into a new
+// value of the type of v, as Unmarshal does, before calling the next handler.
+// The decoded value, a pointer, is available from RequestValue.
+//
+// Bodies that are not valid JSON, or do not satisfy the schema reflected from
+// v, are answered with a 400 and an application/problem+json body listing the
+// JSON Pointer of every problem. Bodies larger than DefaultMaxBodySize, or the
+// limit set with WithMaxBodySize, are answered with a 413.
+func ValidateRequest(v interface{}, opts ...UnmarshalOption) func(http.Handler) http.Handler {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	o := &unmarshalOptions{maxBodySize: DefaultMaxBodySize}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, o.maxBodySize))
+			if err != nil && int64(len(body)) >= o.maxBodySize {
+				// MaxBytesReader fails once the limit has been read.
+				writeProblem(w, &Problem{Status: http.StatusRequestEntityTooLarge, Detail: "request body is larger than " + strconv.FormatInt(o.maxBodySize, 10) + " bytes"})
+				return
+			}
+			if err != nil {
+				writeProblem(w, &Problem{Detail: "could not read request body: " + err.Error()})
+				return
+			}
+			value := reflect.New(t).Interface()
+			if err := Unmarshal(body, value, opts...); err != nil {
+				var verr *ValidationError
+				if !errors.As(err, &verr) {
+					writeProblem(w, &Problem{Detail: "request body is not valid JSON: " + err.Error()})
+					return
+				}
+				problem := &Problem{Detail: "request body does not match the schema"}
+				for _, p := range verr.Problems {
+					problem.Errors = append(problem.Errors, &ProblemError{Pointer: p.Path, Keyword: p.Keyword, Detail: p.Message})
+				}
+				writeProblem(w, problem)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestValueKey{}, value)))
+		})
+	}
+}
+
+// RequestValue returns the request body decoded by ValidateRequest, or nil.
+func RequestValue(r *http.Request) interface{} {
+	return r.Context().Value(requestValueKey{})
+}
+
+// writeProblem writes p, with a 400 status unless p has one.
+func writeProblem(w http.ResponseWriter, p *Problem) {
+	if p.Status == 0 {
+		p.Status = http.StatusBadRequest
+	}
+	p.Type = "about:blank"
+	p.Title = http.StatusText(p.Status)
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(p.Status)
+	_ = json.NewEncoder(w).Encode(p)
+}
@@ -0,0 +1,76 @@
+package jsonschema
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type HTTPCreateUser struct {
+	Name string `json:"name" jsonschema:"minLength=1"`
+	Age  int    `json:"age,omitempty" jsonschema:"minimum=18,default=21"`
+}
+
+func TestValidateRequest(t *testing.T) {
+	var got *HTTPCreateUser
+	handler := ValidateRequest(HTTPCreateUser{}, WithDefaults())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = RequestValue(r).(*HTTPCreateUser)
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	serve := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("POST", "/users", strings.NewReader(body)))
+		return w
+	}
+
+	w := serve(`{"name": "joe"}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.Equal(t, &HTTPCreateUser{Name: "joe", Age: 21}, got)
+
+	w = serve(`{"name": "", "age": 3, "admin": true}`)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+	require.JSONEq(t, `{
+		"type": "about:blank",
+		"title": "Bad Request",
+		"status": 400,
+		"detail": "request body does not match the schema",
+		"errors": [
+			{"pointer": "", "keyword": "additionalProperties", "detail": "unexpected property \"admin\""},
+			{"pointer": "/age", "keyword": "minimum", "detail": "must be at least 18"},
+			{"pointer": "/name", "keyword": "minLength", "detail": "must be at least 1 characters long"}
+		]
+	}`, w.Body.String())
+
+	w = serve(`{"name":`)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Contains(t, w.Body.String(), `"detail":"request body is not valid JSON: unexpected EOF"`)
+
+	require.Nil(t, RequestValue(httptest.NewRequest("GET", "/", nil)))
+}
+
+func TestValidateRequestBodySize(t *testing.T) {
+	handler := ValidateRequest(HTTPCreateUser{}, WithMaxBodySize(16))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+	serve := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("POST", "/users", strings.NewReader(body)))
+		return w
+	}
+
+	require.Equal(t, http.StatusCreated, serve(`{"name": "joe"}`).Code)
+
+	w := serve(`{"name": "josephine"}`)
+	require.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	require.JSONEq(t, `{
+		"type": "about:blank",
+		"title": "Request Entity Too Large",
+		"status": 413,
+		"detail": "request body is larger than 16 bytes"
+	}`, w.Body.String())
+}
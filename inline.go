@@ -0,0 +1,68 @@
+package jsonschema
+
+import "strings"
+
+// ConversionError lists the constructs of a schema that cannot be expressed
+// in the dialect it was converted to. Each problem is prefixed by the JSON
+// Pointer of the offending schema.
+type ConversionError struct {
+	// Dialect describes the target of the conversion.
+	Dialect  string
+	Problems []string
+}
+
+func (e *ConversionError) Error() string {
+	return "jsonschema: schema cannot be expressed as " + e.Dialect + ":\n  " + strings.Join(e.Problems, "\n  ")
+}
+
+// conversionProblems collects the problems found while converting a schema
+// into another dialect.
+type conversionProblems []string
+
+func (p *conversionProblems) problem(path, msg string) {
+	if path == "" {
+		path = "/"
+	}
+	*p = append(*p, path+": "+msg)
+}
+
+// asError returns the problems as a *ConversionError, or nil if there are
+// none.
+func (p conversionProblems) asError(dialect string) error {
+	if len(p) == 0 {
+		return nil
+	}
+	return &ConversionError{Dialect: dialect, Problems: p}
+}
+
+// inliner resolves local references while converting a schema into a dialect
+// that does not support $ref, collecting problems as it goes.
+type inliner struct {
+	conversionProblems
+	definitions Definitions
+	visiting    map[string]bool
+}
+
+func newInliner(definitions Definitions) inliner {
+	return inliner{
+		definitions: definitions,
+		visiting:    map[string]bool{},
+	}
+}
+
+// inline converts the definition t refers to with convert.
+func (c *inliner) inline(t *Type, path string, convert func(*Type, string) *Type) *Type {
+	name, ok := definitionName(t.Ref)
+	def := c.definitions[name]
+	if !ok || def == nil {
+		c.problem(path, "unresolvable $ref "+t.Ref)
+		return &Type{}
+	}
+	if c.visiting[name] {
+		c.problem(path, "recursive reference to "+name+" cannot be inlined")
+		return &Type{}
+	}
+	c.visiting[name] = true
+	defer delete(c.visiting, name)
+	return convert(def, path)
+}
@@ -0,0 +1,256 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+)
+
+// JTD is a JSON Type Definition schema (RFC 8927).
+type JTD struct {
+	Definitions          map[string]*JTD        `json:"definitions,omitempty"`
+	Metadata             map[string]interface{} `json:"metadata,omitempty"`
+	Nullable             bool                   `json:"nullable,omitempty"`
+	Ref                  string                 `json:"ref,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Enum                 []string               `json:"enum,omitempty"`
+	Elements             *JTD                   `json:"elements,omitempty"`
+	Properties           map[string]*JTD        `json:"properties,omitempty"`
+	OptionalProperties   map[string]*JTD        `json:"optionalProperties,omitempty"`
+	AdditionalProperties bool                   `json:"additionalProperties,omitempty"`
+	Values               *JTD                   `json:"values,omitempty"`
+	Discriminator        string                 `json:"discriminator,omitempty"`
+	Mapping              map[string]*JTD        `json:"mapping,omitempty"`
+}
+
+// MarshalJSON keeps an empty, non-nil Properties, so that objects without
+// any properties stay in the properties form rather than the empty form.
+func (j *JTD) MarshalJSON() ([]byte, error) {
+	type jtd JTD
+	b, err := json.Marshal((*jtd)(j))
+	if err != nil {
+		return nil, err
+	}
+	if j.Properties == nil || len(j.Properties) > 0 || len(j.OptionalProperties) > 0 {
+		return b, nil
+	}
+	if bytes.Equal(b, []byte("{}")) {
+		return []byte(`{"properties":{}}`), nil
+	}
+	return append([]byte(`{"properties":{},`), b[1:]...), nil
+}
+
+// JTDSchema converts s into a JSON Type Definition.
+//
+// Integers become int32, numbers float64 and date-time strings timestamp.
+// Optional properties become optionalProperties, nullable oneOfs become
+// nullable, maps become values and a oneOf of objects sharing a required,
+// single-valued string property becomes a discriminator.
+//
+// Keywords that JTD cannot represent, such as pattern or minimum, are dropped
+// and reported in a *ConversionError. The returned schema is usable even when
+// such an error is returned.
+func JTDSchema(s *Schema) (*JTD, error) {
+	c := &jtdConverter{definitions: s.Definitions}
+	root := c.convert(s.Type, "")
+	if len(s.Definitions) > 0 {
+		root.Definitions = map[string]*JTD{}
+		for _, name := range sortedKeys(s.Definitions) {
+			root.Definitions[name] = c.convert(s.Definitions[name], "/definitions/"+pointerEscape(name))
+		}
+	}
+	return root, c.asError("JSON Type Definition")
+}
+
+type jtdConverter struct {
+	conversionProblems
+	definitions Definitions
+}
+
+func (c *jtdConverter) convert(t *Type, path string) *JTD {
+	if sub, ok := nullableOneOf(t); ok {
+		out := c.convert(sub, path)
+		out.Nullable = true
+		return out
+	}
+
+	out := &JTD{}
+	if t.Description != "" {
+		out.Metadata = map[string]interface{}{"description": t.Description}
+	}
+	c.unsupported(t, path)
+
+	if t.Ref != "" {
+		name, ok := definitionName(t.Ref)
+		if !ok {
+			c.problem(path, "unsupported $ref "+t.Ref)
+			return out
+		}
+		out.Ref = name
+		return out
+	}
+
+	if len(t.OneOf) > 0 && t.Type == "" {
+		if !c.discriminator(t, out, path) {
+			c.problem(path, "oneOf cannot be represented")
+		}
+		return out
+	}
+
+	if len(t.Enum) > 0 {
+		for _, v := range t.Enum {
+			s, ok := v.(string)
+			if !ok {
+				c.problem(path, "enum values must be strings")
+				out.Enum = nil
+				break
+			}
+			out.Enum = append(out.Enum, s)
+		}
+		if out.Enum != nil {
+			return out
+		}
+	}
+
+	switch t.Type {
+	case "string":
+		out.Type = "string"
+		switch t.Format {
+		case "":
+		case "date-time":
+			out.Type = "timestamp"
+		default:
+			c.problem(path, "format "+t.Format+" cannot be represented")
+		}
+	case "integer":
+		out.Type = "int32"
+	case "number":
+		out.Type = "float64"
+	case "boolean":
+		out.Type = "boolean"
+	case "array":
+		out.Elements = &JTD{}
+		if t.Items != nil {
+			out.Elements = c.convert(t.Items, path+"/items")
+		}
+	case "object":
+		c.object(t, out, path)
+	case "":
+	default:
+		c.problem(path, "type "+t.Type+" cannot be represented")
+	}
+	return out
+}
+
+func (c *jtdConverter) object(t *Type, out *JTD, path string) {
+	if len(t.PatternProperties) > 0 {
+		if t.Properties != nil && len(t.Properties.Keys()) > 0 {
+			c.problem(path, "properties and patternProperties cannot be combined")
+		}
+		if len(t.PatternProperties) > 1 {
+			c.problem(path, "multiple patternProperties cannot be represented")
+		}
+		for _, key := range sortedKeys(t.PatternProperties) {
+			if key != ".*" {
+				c.problem(path, "patternProperties key pattern "+key+" cannot be represented")
+			}
+			out.Values = c.convert(t.PatternProperties[key], path+"/patternProperties/"+pointerEscape(key))
+			return
+		}
+	}
+	if len(t.OneOf) > 0 {
+		c.problem(path, "oneOf cannot be represented")
+	}
+	out.Properties = map[string]*JTD{}
+	out.AdditionalProperties = string(t.AdditionalProperties) == "true"
+	if t.Properties == nil {
+		return
+	}
+	for _, key := range t.Properties.Keys() {
+		v, _ := t.Properties.Get(key)
+		p, ok := v.(*Type)
+		if !ok {
+			c.problem(path+"/properties/"+pointerEscape(key), "property is not a schema")
+			continue
+		}
+		converted := c.convert(p, path+"/properties/"+pointerEscape(key))
+		if contains(t.Required, key) {
+			out.Properties[key] = converted
+		} else {
+			if out.OptionalProperties == nil {
+				out.OptionalProperties = map[string]*JTD{}
+			}
+			out.OptionalProperties[key] = converted
+		}
+	}
+}
+
+// discriminator converts a oneOf of objects that all require the same
+// property, each with a single string enum value, into a discriminator.
+func (c *jtdConverter) discriminator(t *Type, out *JTD, path string) bool {
+	tag := ""
+	var objects []*Type
+	var values []string
+	for _, sub := range t.OneOf {
+		if name, ok := definitionName(sub.Ref); ok {
+			sub = c.definitions[name]
+		}
+		if sub == nil || sub.Type != "object" || sub.Properties == nil {
+			return false
+		}
+		found := ""
+		for _, key := range sub.Required {
+			v, _ := sub.Properties.Get(key)
+			if p, ok := v.(*Type); ok && len(p.Enum) == 1 {
+				if s, ok := p.Enum[0].(string); ok && (tag == "" || tag == key) {
+					found = key
+					values = append(values, s)
+					break
+				}
+			}
+		}
+		if found == "" {
+			return false
+		}
+		tag = found
+		objects = append(objects, sub)
+	}
+	out.Discriminator = tag
+	out.Mapping = map[string]*JTD{}
+	for i, sub := range objects {
+		mapping := c.convert(sub, path+"/oneOf/"+strconv.Itoa(i))
+		delete(mapping.Properties, tag)
+		out.Mapping[values[i]] = mapping
+	}
+	return true
+}
+
+// unsupported reports the validation keywords of t that JTD cannot represent.
+func (c *jtdConverter) unsupported(t *Type, path string) {
+	keywords := []struct {
+		name    string
+		present bool
+	}{
+		{"multipleOf", t.MultipleOf != 0},
+		{"maximum", t.Maximum != 0 || t.ExclusiveMaximum},
+		{"minimum", t.Minimum != 0 || t.ExclusiveMinimum},
+		{"maxLength", t.MaxLength != 0},
+		{"minLength", t.MinLength != 0},
+		{"pattern", t.Pattern != ""},
+		{"additionalItems", t.AdditionalItems != nil},
+		{"maxItems", t.MaxItems != 0},
+		{"minItems", t.MinItems != 0},
+		{"uniqueItems", t.UniqueItems},
+		{"maxProperties", t.MaxProperties != 0},
+		{"minProperties", t.MinProperties != 0},
+		{"dependencies", len(t.Dependencies) > 0},
+		{"allOf", len(t.AllOf) > 0},
+		{"anyOf", len(t.AnyOf) > 0},
+		{"not", t.Not != nil},
+	}
+	for _, k := range keywords {
+		if k.present {
+			c.problem(path, k.name+" cannot be represented")
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/iancoleman/orderedmap"
+	"github.com/stretchr/testify/require"
+)
+
+type JTDEvent struct {
+	ID      string            `json:"id" jsonschema:"description=event id"`
+	Kind    string            `json:"kind" jsonschema:"enum=created,enum=deleted"`
+	At      time.Time         `json:"at"`
+	Count   int               `json:"count,omitempty"`
+	Tags    []string          `json:"tags,omitempty"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	Parent  *JTDEvent         `json:"parent,omitempty" jsonschema:"nullable"`
+	Payload interface{}       `json:"payload,omitempty"`
+	Empty   JTDEmpty          `json:"empty"`
+}
+
+type JTDEmpty struct{}
+
+type JTDLossy struct {
+	Name  string `json:"name" jsonschema:"pattern=^[a-z]+$,minLength=1"`
+	Email string `json:"email" jsonschema:"format=email"`
+	Age   int    `json:"age" jsonschema:"minimum=18"`
+}
+
+func TestJTDSchema(t *testing.T) {
+	actual, err := JTDSchema(Reflect(&JTDEvent{}))
+	require.NoError(t, err)
+	actualJSON, err := json.MarshalIndent(actual, "", "  ")
+	require.NoError(t, err)
+	expectedJSON, err := ioutil.ReadFile("fixtures/jtd.json")
+	require.NoError(t, err)
+	require.JSONEq(t, string(expectedJSON), string(actualJSON))
+}
+
+func TestJTDSchemaLossy(t *testing.T) {
+	actual, err := JTDSchema(Reflect(&JTDLossy{}))
+	require.Error(t, err)
+	require.Equal(t, []string{
+		"/definitions/JTDLossy/properties/name: minLength cannot be represented",
+		"/definitions/JTDLossy/properties/name: pattern cannot be represented",
+		"/definitions/JTDLossy/properties/email: format email cannot be represented",
+		"/definitions/JTDLossy/properties/age: minimum cannot be represented",
+	}, err.(*ConversionError).Problems)
+	require.Equal(t, "JSON Type Definition", err.(*ConversionError).Dialect)
+	require.Equal(t, "string", actual.Definitions["JTDLossy"].Properties["name"].Type)
+}
+
+func TestJTDSchemaDiscriminator(t *testing.T) {
+	variant := func(kind string, field string) *Type {
+		properties := orderedmap.New()
+		properties.Set("type", &Type{Type: "string", Enum: []interface{}{kind}})
+		properties.Set(field, &Type{Type: "string"})
+		return &Type{Type: "object", Properties: properties, Required: []string{"type", field}}
+	}
+	s := &Schema{
+		Type: &Type{OneOf: []*Type{{Ref: "#/definitions/Cat"}, {Ref: "#/definitions/Dog"}}},
+		Definitions: Definitions{
+			"Cat": variant("cat", "meow"),
+			"Dog": variant("dog", "bark"),
+		},
+	}
+	actual, err := JTDSchema(s)
+	require.NoError(t, err)
+	actualJSON, err := json.Marshal(actual)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"definitions": {
+			"Cat": {"properties": {"type": {"enum": ["cat"]}, "meow": {"type": "string"}}},
+			"Dog": {"properties": {"type": {"enum": ["dog"]}, "bark": {"type": "string"}}}
+		},
+		"discriminator": "type",
+		"mapping": {
+			"cat": {"properties": {"meow": {"type": "string"}}},
+			"dog": {"properties": {"bark": {"type": "string"}}}
+		}
+	}`, string(actualJSON))
+}
@@ -0,0 +1,123 @@
+package jsonschema
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// A Loader loads the schema identified by an absolute URI, such as the target
+// of a $ref to another document. URIs are passed without their fragment.
+type Loader interface {
+	Load(uri string) (*Schema, error)
+}
+
+// ErrSchemaNotFound is returned, possibly wrapped, by Loaders that have no
+// schema for a URI.
+var ErrSchemaNotFound = errors.New("jsonschema: schema not found")
+
+// MapLoader loads schemas from memory, keyed by URI.
+type MapLoader map[string]*Schema
+
+// Load implements Loader.
+func (m MapLoader) Load(uri string) (*Schema, error) {
+	if s, ok := m[trimFragment(uri)]; ok {
+		return s, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrSchemaNotFound, uri)
+}
+
+// FSLoader loads schemas from the files of FS, such as an embed.FS. URIs
+// starting with BaseURI are loaded from the path that follows it.
+type FSLoader struct {
+	FS      fs.FS
+	BaseURI string
+}
+
+// Load implements Loader.
+func (l *FSLoader) Load(uri string) (*Schema, error) {
+	uri = trimFragment(uri)
+	if !strings.HasPrefix(uri, l.BaseURI) {
+		return nil, fmt.Errorf("%w: %s", ErrSchemaNotFound, uri)
+	}
+	name := strings.TrimPrefix(uri, l.BaseURI)
+	data, err := fs.ReadFile(l.FS, name)
+	if errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrInvalid) {
+		return nil, fmt.Errorf("%w: %s", ErrSchemaNotFound, uri)
+	} else if err != nil {
+		return nil, fmt.Errorf("jsonschema: %w", err)
+	}
+	s := &Schema{}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("jsonschema: %s: %w", name, err)
+	}
+	return s, nil
+}
+
+// MultiLoader tries each of its Loaders in turn, returning the first schema
+// found.
+type MultiLoader []Loader
+
+// Load implements Loader.
+func (m MultiLoader) Load(uri string) (*Schema, error) {
+	for _, l := range m {
+		s, err := l.Load(uri)
+		if !errors.Is(err, ErrSchemaNotFound) {
+			return s, err
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrSchemaNotFound, uri)
+}
+
+//go:embed metaschemas/*.json
+var metaSchemaFiles embed.FS
+
+// MetaSchemas loads the JSON Schema meta-schemas bundled with the package,
+// so that they resolve without network access. It is the Loader used by
+// Validator and Bundler when none is configured.
+var MetaSchemas Loader = MapLoader{
+	"http://json-schema.org/draft-04/schema": mustLoadMetaSchema("draft-04.json"),
+}
+
+func mustLoadMetaSchema(name string) *Schema {
+	s, err := (&FSLoader{FS: metaSchemaFiles}).Load(path.Join("metaschemas", name))
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func trimFragment(uri string) string {
+	if i := strings.Index(uri, "#"); i >= 0 {
+		return uri[:i]
+	}
+	return uri
+}
+
+// resolveURI resolves ref against base, dropping any fragment.
+func resolveURI(base, ref string) (string, error) {
+	r, err := url.Parse(ref)
+	if err != nil {
+		return "", err
+	}
+	if base != "" {
+		b, err := url.Parse(base)
+		if err != nil {
+			return "", err
+		}
+		r = b.ResolveReference(r)
+	}
+	r.Fragment = ""
+	return r.String(), nil
+}
+
+// isAbsoluteURI reports whether ref has a scheme, such as http.
+func isAbsoluteURI(ref string) bool {
+	u, err := url.Parse(trimFragment(ref))
+	return err == nil && u.IsAbs()
+}
@@ -0,0 +1,122 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoaders(t *testing.T) {
+	common := &Schema{Type: &Type{Type: "string"}}
+	fsys := fstest.MapFS{
+		"types.json": {Data: []byte(`{"definitions": {"ID": {"type": "integer"}}}`)},
+		"bad.json":   {Data: []byte(`{`)},
+	}
+	loader := MultiLoader{
+		MapLoader{"https://example.com/common.json": common},
+		&FSLoader{FS: fsys, BaseURI: "https://example.com/schemas/"},
+		MetaSchemas,
+	}
+
+	s, err := loader.Load("https://example.com/common.json#/definitions/X")
+	require.NoError(t, err)
+	require.Equal(t, common, s)
+
+	s, err = loader.Load("https://example.com/schemas/types.json")
+	require.NoError(t, err)
+	require.Equal(t, "integer", s.Definitions["ID"].Type)
+
+	s, err = loader.Load("http://json-schema.org/draft-04/schema#")
+	require.NoError(t, err)
+	require.Equal(t, "Core schema meta-schema", s.Description)
+
+	_, err = loader.Load("https://example.com/schemas/bad.json")
+	require.EqualError(t, err, "jsonschema: bad.json: unexpected end of JSON input")
+
+	for _, uri := range []string{"https://example.com/schemas/none.json", "https://example.org/common.json"} {
+		_, err = loader.Load(uri)
+		require.True(t, errors.Is(err, ErrSchemaNotFound), uri)
+		require.EqualError(t, err, "jsonschema: schema not found: "+uri)
+	}
+}
+
+func TestValidateMetaSchema(t *testing.T) {
+	meta := &Schema{Type: &Type{Ref: "http://json-schema.org/draft-04/schema#"}}
+	b, err := json.Marshal(Reflect(&TestUser{}))
+	require.NoError(t, err)
+	require.NoError(t, Validate(meta, b))
+
+	err = Validate(meta, []byte(`{"type": "thing", "minLength": -1, "required": []}`))
+	require.IsType(t, &ValidationError{}, err)
+	paths := map[string]bool{}
+	for _, p := range err.(*ValidationError).Problems {
+		paths[p.Path] = true
+	}
+	require.Equal(t, map[string]bool{"/minLength": true, "/required": true, "/type": true}, paths)
+}
+
+func TestValidateRemoteRefs(t *testing.T) {
+	g := NewRegistry("https://example.com/schemas/", nil)
+	require.NoError(t, g.Register(&RegistryOrder{}, RegistryCustomer{}))
+	order, ok := g.Schema("RegistryOrder")
+	require.True(t, ok)
+
+	v := &Validator{Loader: g}
+	require.NoError(t, v.Validate(order, []byte(`{"customer": {"name": "joe", "address": {"city": "x"}}, "items": []}`)))
+	err := v.Validate(order, []byte(`{"customer": {"name": "joe", "address": {}}, "items": []}`))
+	require.Contains(t, err.Error(), `/customer/address: missing required property "city"`)
+
+	err = Validate(order, []byte(`{"customer": {}, "items": []}`))
+	require.Contains(t, err.Error(), "/customer: cannot load $ref https://example.com/schemas/RegistryCustomer.json: jsonschema: schema not found: https://example.com/schemas/RegistryCustomer.json")
+
+	relative := &Schema{Type: &Type{ID: "https://example.com/schemas/Wrapper.json", Ref: "RegistryCustomer.json#/definitions/RegistryAddress"}}
+	require.NoError(t, v.Validate(relative, []byte(`{"city": "x"}`)))
+	require.Error(t, v.Validate(relative, []byte(`{"city": 1}`)))
+}
+
+func TestBundleRemoteRefs(t *testing.T) {
+	fsys := fstest.MapFS{
+		"order.json": {Data: []byte(`{
+			"type": "object",
+			"properties": {
+				"customer": {"$ref": "https://example.com/schemas/RegistryCustomer.json"},
+				"schema": {"$ref": "http://json-schema.org/draft-04/schema#/definitions/positiveInteger"}
+			}
+		}`)},
+	}
+	g := NewRegistry("https://example.com/schemas/", nil)
+	require.NoError(t, g.Register(RegistryCustomer{}))
+	s, err := (&Bundler{Loader: MultiLoader{g, MetaSchemas}}).Bundle(fsys, "order.json")
+	require.NoError(t, err)
+	b, err := json.Marshal(s)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"customer": {"$ref": "#/definitions/RegistryCustomer"},
+			"schema": {"$ref": "#/definitions/positiveInteger"}
+		},
+		"definitions": {
+			"RegistryCustomer": {"$ref": "#/definitions/RegistryCustomer_2"},
+			"RegistryCustomer_2": {
+				"type": "object",
+				"required": ["name", "address"],
+				"properties": {
+					"name": {"type": "string"},
+					"address": {"$schema": "http://json-schema.org/draft-04/schema#", "$ref": "#/definitions/RegistryAddress"}
+				},
+				"additionalProperties": false
+			},
+			"RegistryAddress": {
+				"type": "object",
+				"required": ["city"],
+				"properties": {"city": {"type": "string"}},
+				"additionalProperties": false
+			},
+			"positiveInteger": {"type": "integer", "minimum": 0}
+		}
+	}`, string(b))
+}
@@ -0,0 +1,192 @@
+package jsonschema
+
+import (
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	genericPackagePath = regexp.MustCompile(`(?:[\w.~-]+/)*[\w.~-]*\.(\w+)`)
+	genericSeparators  = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+)
+
+// genericTypeName turns the name of an instantiated generic type, such as
+// "Page[github.com/x/y.User]", into an identifier that needs no escaping in
+// JSON Pointers or URIs, such as "Page_User". Package paths of the type
+// arguments are dropped and pointers are spelled Ptr, so that Page[*User]
+// becomes "Page_PtrUser"; other names are returned unchanged.
+func genericTypeName(name string) string {
+	if !strings.Contains(name, "[") {
+		return name
+	}
+	name = genericPackagePath.ReplaceAllString(name, "$1")
+	name = strings.ReplaceAll(name, "*", "Ptr")
+	return strings.Trim(genericSeparators.ReplaceAllString(name, "_"), "_")
+}
+
+// typeNames records the types given each definition name while reflecting a
+// schema, so that distinct types sharing a name can be renamed.
+type typeNames struct {
+	types   map[string][]reflect.Type
+	renamed map[reflect.Type]string
+}
+
+// reflectNamed calls fn with a copy of r that records the types given each
+// definition name and, if distinct types shared a name, calls it again with
+// those types renamed. It returns the copy used last, whose typeName agrees
+// with the definitions fn produced.
+func (r *Reflector) reflectNamed(fn func(r *Reflector) error) (*Reflector, error) {
+	rr := *r
+	rr.names = &typeNames{types: map[string][]reflect.Type{}}
+	if err := fn(&rr); err != nil {
+		return nil, err
+	}
+	if renamed := rr.names.disambiguate(); renamed != nil {
+		rr.names = &typeNames{types: map[string][]reflect.Type{}, renamed: renamed}
+		if err := fn(&rr); err != nil {
+			return nil, err
+		}
+	}
+	return &rr, nil
+}
+
+func (n *typeNames) add(name string, t reflect.Type) {
+	if n == nil || name == "" {
+		return
+	}
+	for _, seen := range n.types[name] {
+		if seen == t {
+			return
+		}
+	}
+	n.types[name] = append(n.types[name], t)
+}
+
+// disambiguate returns new names for the types that share a name, or nil if
+// there are none.
+func (n *typeNames) disambiguate() map[reflect.Type]string {
+	var renamed map[reflect.Type]string
+	names := make([]string, 0, len(n.types))
+	for name := range n.types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		types := n.types[name]
+		if len(types) < 2 {
+			continue
+		}
+		if renamed == nil {
+			renamed = map[reflect.Type]string{}
+		}
+		for i, t := range types {
+			renamed[t] = uniqueTypeName(name, t, types, i)
+		}
+	}
+	return renamed
+}
+
+// uniqueTypeName prefixes name, the name of types[i], with the fewest trailing
+// elements of its package path that no other type in types shares, falling
+// back to a numeric suffix.
+func uniqueTypeName(name string, t reflect.Type, types []reflect.Type, i int) string {
+	for k := 1; ; k++ {
+		prefix, ok := pkgPathSuffix(t, k)
+		if !ok {
+			return name + "_" + strconv.Itoa(i+1)
+		}
+		unique := true
+		for j, other := range types {
+			if otherPrefix, _ := pkgPathSuffix(other, k); j != i && otherPrefix == prefix {
+				unique = false
+				break
+			}
+		}
+		if unique {
+			return prefix + "." + name
+		}
+	}
+}
+
+// pkgPathSuffix returns the last k elements of the package path of t, joined
+// with dots. It reports false if the path has fewer elements.
+func pkgPathSuffix(t reflect.Type, k int) (string, bool) {
+	if t.PkgPath() == "" {
+		return "", false
+	}
+	elements := strings.Split(t.PkgPath(), "/")
+	if k > len(elements) {
+		return "", false
+	}
+	return strings.Join(elements[len(elements)-k:], "."), true
+}
+
+// SnakeCase names fields in snake_case, as in "http_server_url" for
+// HTTPServerURL. It is a FieldNamer.
+func SnakeCase(name string) string {
+	return strings.Join(fieldWords(name), "_")
+}
+
+// KebabCase names fields in kebab-case, as in "http-server-url" for
+// HTTPServerURL. It is a FieldNamer.
+func KebabCase(name string) string {
+	return strings.Join(fieldWords(name), "-")
+}
+
+// CamelCase names fields in camelCase, as in "httpServerUrl" for
+// HTTPServerURL. It is a FieldNamer.
+func CamelCase(name string) string {
+	words := fieldWords(name)
+	for i := 1; i < len(words); i++ {
+		words[i] = strings.ToUpper(words[i][:1]) + words[i][1:]
+	}
+	return strings.Join(words, "")
+}
+
+// LowerCase names fields in lower case, as in "httpserverurl" for
+// HTTPServerURL. It is a FieldNamer.
+func LowerCase(name string) string {
+	return strings.ToLower(name)
+}
+
+// fieldWords splits a Go identifier into lower case words, keeping acronyms
+// and trailing digits together: "HTTPServer2URL" is "http", "server2", "url".
+func fieldWords(name string) []string {
+	runes := []rune(name)
+	var words []string
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		prev, cur := runes[i-1], runes[i]
+		next := rune(0)
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+		switch {
+		case cur == '_':
+			if i > start {
+				words = append(words, string(runes[start:i]))
+			}
+			start = i + 1
+			continue
+		case unicode.IsUpper(cur) && (unicode.IsLower(prev) || unicode.IsDigit(prev)):
+		case unicode.IsUpper(cur) && unicode.IsUpper(prev) && unicode.IsLower(next):
+		default:
+			continue
+		}
+		if i > start {
+			words = append(words, string(runes[start:i]))
+		}
+		start = i
+	}
+	if start < len(runes) {
+		words = append(words, string(runes[start:]))
+	}
+	for i, word := range words {
+		words[i] = strings.ToLower(word)
+	}
+	return words
+}
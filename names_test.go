@@ -0,0 +1,263 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"go/token"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type NamesFiles struct {
+	Local  os.File     `json:"local"`
+	Source *token.File `json:"source"`
+	Opened []os.File   `json:"opened"`
+}
+
+func TestDefinitionNameCollisions(t *testing.T) {
+	b, err := json.Marshal(Reflect(&NamesFiles{}))
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"$schema": "http://json-schema.org/draft-04/schema#",
+		"$ref": "#/definitions/NamesFiles",
+		"definitions": {
+			"NamesFiles": {
+				"type": "object",
+				"required": ["local", "source", "opened"],
+				"properties": {
+					"local": {"$schema": "http://json-schema.org/draft-04/schema#", "$ref": "#/definitions/os.File"},
+					"source": {"$schema": "http://json-schema.org/draft-04/schema#", "$ref": "#/definitions/token.File"},
+					"opened": {"type": "array", "items": {"$ref": "#/definitions/os.File"}}
+				},
+				"additionalProperties": false
+			},
+			"os.File": {"type": "object", "properties": {}, "additionalProperties": false},
+			"token.File": {"type": "object", "properties": {}, "additionalProperties": false}
+		}
+	}`, string(b))
+}
+
+func TestUniqueTypeName(t *testing.T) {
+	local := func() reflect.Type {
+		type Config struct{}
+		return reflect.TypeOf(Config{})
+	}()
+	other := func() reflect.Type {
+		type Config struct{}
+		return reflect.TypeOf(Config{})
+	}()
+	names := &typeNames{types: map[string][]reflect.Type{}}
+	names.add("Config", local)
+	names.add("Config", local)
+	names.add("Config", other)
+	names.add("File", reflect.TypeOf(os.File{}))
+	names.add("", reflect.TypeOf(struct{}{}))
+	require.Equal(t, map[reflect.Type]string{local: "Config_1", other: "Config_2"}, names.disambiguate())
+
+	names = &typeNames{types: map[string][]reflect.Type{}}
+	names.add("File", reflect.TypeOf(os.File{}))
+	require.Nil(t, names.disambiguate())
+}
+
+type NamesAnonymous struct {
+	Point struct {
+		X int `json:"x"`
+		Y int `json:"y"`
+	} `json:"point"`
+	Tags []struct {
+		Name string `json:"name"`
+	} `json:"tags"`
+}
+
+func TestAnonymousStructs(t *testing.T) {
+	b, err := json.Marshal(Reflect(&NamesAnonymous{}))
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"$schema": "http://json-schema.org/draft-04/schema#",
+		"$ref": "#/definitions/NamesAnonymous",
+		"definitions": {
+			"NamesAnonymous": {
+				"type": "object",
+				"required": ["point", "tags"],
+				"properties": {
+					"point": {
+						"type": "object",
+						"required": ["x", "y"],
+						"properties": {"x": {"type": "integer"}, "y": {"type": "integer"}},
+						"additionalProperties": false
+					},
+					"tags": {
+						"type": "array",
+						"items": {
+							"type": "object",
+							"required": ["name"],
+							"properties": {"name": {"type": "string"}},
+							"additionalProperties": false
+						}
+					}
+				},
+				"additionalProperties": false
+			}
+		}
+	}`, string(b))
+}
+
+func TestAnonymousStructsFullyQualified(t *testing.T) {
+	s := (&Reflector{FullyQualifyTypeNames: true}).Reflect(&NamesAnonymous{})
+	require.Equal(t, []string{"github.com/alecthomas/jsonschema.NamesAnonymous"}, sortedKeys(s.Definitions))
+	st := s.Definitions["github.com/alecthomas/jsonschema.NamesAnonymous"]
+	point, _ := st.Properties.Get("point")
+	require.Equal(t, "", point.(*Type).Ref)
+	require.Equal(t, []string{"x", "y"}, point.(*Type).Required)
+	tags, _ := st.Properties.Get("tags")
+	require.Equal(t, []string{"name"}, tags.(*Type).Items.Required)
+}
+
+func TestGenericTypeName(t *testing.T) {
+	for name, expected := range map[string]string{
+		"User":                                    "User",
+		"Page[github.com/x/y.User]":               "Page_User",
+		"Page[int]":                               "Page_int",
+		"Pair[string,gopkg.in/yaml.v3.Node]":      "Pair_string_Node",
+		"Page[map[string]github.com/x/y.User]":    "Page_map_string_User",
+		"Page[*github.com/x/y-z.User]":            "Page_PtrUser",
+		"Page[**github.com/x/y-z.User]":           "Page_PtrPtrUser",
+		"Map[string,*int]":                        "Map_string_Ptrint",
+		"Tree[github.com/x/y.Node[time.Time]]":    "Tree_Node_Time",
+		"List[[]github.com/x/y.Item,example/z.T]": "List_Item_T",
+	} {
+		require.Equal(t, expected, genericTypeName(name), name)
+	}
+}
+
+func TestFieldNamers(t *testing.T) {
+	for name, expected := range map[string][4]string{
+		"TestFlag":       {"test_flag", "test-flag", "testFlag", "testflag"},
+		"ID":             {"id", "id", "id", "id"},
+		"UserID":         {"user_id", "user-id", "userId", "userid"},
+		"HTTPServerURL":  {"http_server_url", "http-server-url", "httpServerUrl", "httpserverurl"},
+		"Base64Data":     {"base64_data", "base64-data", "base64Data", "base64data"},
+		"Server2URL":     {"server2_url", "server2-url", "server2Url", "server2url"},
+		"Already_Snaked": {"already_snaked", "already-snaked", "alreadySnaked", "already_snaked"},
+	} {
+		actual := [4]string{SnakeCase(name), KebabCase(name), CamelCase(name), LowerCase(name)}
+		require.Equal(t, expected, actual, name)
+	}
+}
+
+type NamesEmbedded struct {
+	EmbeddedValue int
+}
+
+type NamesFields struct {
+	NamesEmbedded
+	UserID     int    `json:"id"`
+	MaxRetries int    `json:",omitempty"`
+	HomeDir    string `yaml:"home"`
+	LogLevel   string
+	private    string
+}
+
+func TestReflectorFieldNamer(t *testing.T) {
+	s := (&Reflector{FieldNamer: SnakeCase}).Reflect(&NamesFields{})
+	def := s.Definitions["NamesFields"]
+	require.Equal(t, []string{"embedded_value", "id", "max_retries", "home", "log_level"}, def.Properties.Keys())
+	require.Equal(t, []string{"embedded_value", "id", "home", "log_level"}, def.Required)
+
+	s = Reflect(&NamesFields{})
+	require.Equal(t, []string{"EmbeddedValue", "id", "MaxRetries", "home", "LogLevel"}, s.Definitions["NamesFields"].Properties.Keys())
+}
+
+type NamesDatabase struct {
+	Host string `mapstructure:"host" toml:"host"`
+	Port int    `mapstructure:"port,omitempty" toml:"port,omitempty"`
+}
+
+type NamesConfig struct {
+	NamesDatabase `mapstructure:",squash" toml:"database"`
+	LogLevel      string            `mapstructure:"log_level" toml:"log-level" json:"logLevel"`
+	Secret        string            `mapstructure:"-" toml:"secret"`
+	Labels        map[string]string `koanf:"labels" toml:"labels,inline"`
+}
+
+func TestReflectorTagKeys(t *testing.T) {
+	s := (&Reflector{TagKeys: []string{"mapstructure", "koanf"}}).Reflect(&NamesConfig{})
+	def := s.Definitions["NamesConfig"]
+	require.Equal(t, []string{"host", "port", "log_level", "labels"}, def.Properties.Keys())
+	require.Equal(t, []string{"host", "log_level", "labels"}, def.Required)
+
+	s = (&Reflector{TagKeys: []string{"toml"}}).Reflect(&NamesConfig{})
+	def = s.Definitions["NamesConfig"]
+	require.Equal(t, []string{"database", "log-level", "secret", "labels"}, def.Properties.Keys())
+	database, _ := def.Properties.Get("database")
+	require.Equal(t, "#/definitions/NamesDatabase", database.(*Type).Ref)
+
+	s = Reflect(&NamesConfig{})
+	require.Equal(t, []string{"Host", "Port", "logLevel", "Secret", "Labels"}, s.Definitions["NamesConfig"].Properties.Keys())
+}
+
+func TestParseFieldTag(t *testing.T) {
+	for _, test := range []struct {
+		key, tag string
+		expected fieldTag
+	}{
+		{"json", "name,omitempty", fieldTag{name: "name", omitEmpty: true}},
+		{"json", "-", fieldTag{name: "-", ignored: true}},
+		{"json", ",inline", fieldTag{}},
+		{"yaml", ",inline", fieldTag{inline: true}},
+		{"mapstructure", ",squash", fieldTag{inline: true}},
+		{"mapstructure", ",inline", fieldTag{}},
+		{"toml", "name,inline", fieldTag{name: "name"}},
+		{"form", "name,inline,omitempty", fieldTag{name: "name", inline: true, omitEmpty: true}},
+	} {
+		require.Equal(t, test.expected, parseFieldTag(test.key, test.tag), test.key+":"+test.tag)
+	}
+}
+
+type NamesLocal struct {
+	File os.File `json:"file"`
+}
+
+type NamesSource struct {
+	File token.File `json:"file"`
+}
+
+func TestOpenAPIDefinitionNameCollisions(t *testing.T) {
+	b := NewOpenAPIBuilder(nil)
+	require.NoError(t, b.Add(Endpoint{Method: "GET", Path: "/local", Responses: map[int]interface{}{200: &NamesLocal{}}}))
+	require.NoError(t, b.Add(Endpoint{Method: "GET", Path: "/source", Responses: map[int]interface{}{200: &NamesSource{}}}))
+	doc := b.Document(OpenAPIInfo{Title: "files", Version: "1"})
+	require.Equal(t, []string{"NamesLocal", "NamesSource", "os.File", "token.File"}, sortedKeys(doc.Components.Schemas))
+	file, _ := doc.Components.Schemas["NamesSource"].Properties.Get("file")
+	require.Equal(t, "#/components/schemas/token.File", file.(*Type).Ref)
+}
+
+func TestOpenRPCDefinitionNameCollisions(t *testing.T) {
+	b := NewOpenRPCBuilder(nil)
+	require.NoError(t, b.AddFunc("local", func() NamesLocal { return NamesLocal{} }))
+	require.NoError(t, b.AddFunc("source", func(*NamesSource) {}))
+	doc := b.Document(OpenRPCInfo{Title: "files", Version: "1"})
+	require.Equal(t, []string{"NamesLocal", "NamesSource", "os.File", "token.File"}, sortedKeys(doc.Components.Schemas))
+	file, _ := doc.Components.Schemas["NamesLocal"].Properties.Get("file")
+	require.Equal(t, "#/components/schemas/os.File", file.(*Type).Ref)
+}
+
+func TestRegistryDefinitionNameCollisions(t *testing.T) {
+	g := NewRegistry("https://example.com/schemas/", nil)
+	require.NoError(t, g.Register(&NamesFiles{}))
+	s, ok := g.Schema("NamesFiles")
+	require.True(t, ok)
+	require.Equal(t, []string{"NamesFiles", "os.File", "token.File"}, sortedKeys(s.Definitions))
+}
+
+func TestAvroDefinitionNameCollisions(t *testing.T) {
+	schema, err := (&Reflector{}).ReflectAvro(&NamesFiles{})
+	require.NoError(t, err)
+	record := schema.(*AvroRecord)
+	require.Equal(t, "NamesFiles", record.Name)
+	require.Equal(t, "os.File", record.Fields[0].Type.(*AvroRecord).Name)
+	require.Equal(t, []interface{}{"null", &AvroRecord{Type: "record", Name: "token.File", Fields: []*AvroField{}}}, record.Fields[1].Type)
+	require.Equal(t, &AvroArray{Type: "array", Items: "os.File"}, record.Fields[2].Type)
+}
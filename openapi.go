@@ -0,0 +1,376 @@
+package jsonschema
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// OpenAPIVersion is the OpenAPI specification version emitted by OpenAPIBuilder.
+var OpenAPIVersion = "3.1.0"
+
+// OpenAPIDocument is the root of an OpenAPI document.
+type OpenAPIDocument struct {
+	OpenAPI    string                      `json:"openapi"`
+	Info       OpenAPIInfo                 `json:"info"`
+	Paths      map[string]*OpenAPIPathItem `json:"paths"`
+	Components *OpenAPIComponents          `json:"components,omitempty"`
+}
+
+// OpenAPIInfo provides metadata about the API.
+type OpenAPIInfo struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// OpenAPIComponents holds the reusable schemas referenced from operations.
+type OpenAPIComponents struct {
+	Schemas Definitions `json:"schemas,omitempty"`
+}
+
+// OpenAPIPathItem describes the operations available on a single path.
+type OpenAPIPathItem struct {
+	Get     *OpenAPIOperation `json:"get,omitempty"`
+	Put     *OpenAPIOperation `json:"put,omitempty"`
+	Post    *OpenAPIOperation `json:"post,omitempty"`
+	Delete  *OpenAPIOperation `json:"delete,omitempty"`
+	Options *OpenAPIOperation `json:"options,omitempty"`
+	Head    *OpenAPIOperation `json:"head,omitempty"`
+	Patch   *OpenAPIOperation `json:"patch,omitempty"`
+	Trace   *OpenAPIOperation `json:"trace,omitempty"`
+}
+
+// OpenAPIOperation describes a single API operation on a path.
+type OpenAPIOperation struct {
+	OperationID string                      `json:"operationId,omitempty"`
+	Summary     string                      `json:"summary,omitempty"`
+	Description string                      `json:"description,omitempty"`
+	Tags        []string                    `json:"tags,omitempty"`
+	Parameters  []*OpenAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *OpenAPIRequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*OpenAPIResponse `json:"responses"`
+}
+
+// OpenAPIParameter describes a single path, query or header parameter.
+type OpenAPIParameter struct {
+	Name        string `json:"name"`
+	In          string `json:"in"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+	Schema      *Type  `json:"schema"`
+}
+
+// OpenAPIRequestBody describes the body of a request.
+type OpenAPIRequestBody struct {
+	Required bool                         `json:"required,omitempty"`
+	Content  map[string]*OpenAPIMediaType `json:"content"`
+}
+
+// OpenAPIResponse describes a single response from an operation.
+type OpenAPIResponse struct {
+	Description string                       `json:"description"`
+	Content     map[string]*OpenAPIMediaType `json:"content,omitempty"`
+}
+
+// OpenAPIMediaType provides the schema for a given media type.
+type OpenAPIMediaType struct {
+	Schema *Type `json:"schema"`
+}
+
+// Endpoint describes an operation in terms of Go types.
+//
+// Params must be a struct (or pointer to struct) whose fields are tagged with
+// one of `path:"name"`, `query:"name"` or `header:"name"`. Request and the
+// values of Responses are reflected into JSON bodies.
+type Endpoint struct {
+	Method      string
+	Path        string
+	OperationID string
+	Summary     string
+	Description string
+	Tags        []string
+
+	Params    interface{}
+	Request   interface{}
+	Responses map[int]interface{}
+}
+
+// OpenAPIBuilder collects endpoints into an OpenAPI document, sharing
+// definitions between all of them.
+type OpenAPIBuilder struct {
+	reflector *Reflector
+	endpoints []Endpoint
+}
+
+// NewOpenAPIBuilder creates a builder reflecting types with r.
+// If r is nil the default Reflector is used.
+func NewOpenAPIBuilder(r *Reflector) *OpenAPIBuilder {
+	if r == nil {
+		r = &Reflector{}
+	}
+	return &OpenAPIBuilder{reflector: r}
+}
+
+// paramLocations are the struct tags recognised on Endpoint.Params fields.
+var paramLocations = []string{"path", "query", "header"}
+
+// Add checks e and adds it to the document. Its types are reflected by
+// Document.
+func (b *OpenAPIBuilder) Add(e Endpoint) error {
+	for _, other := range b.endpoints {
+		if strings.EqualFold(other.Method, e.Method) && other.Path == e.Path {
+			return fmt.Errorf("%s %s: duplicate operation", e.Method, e.Path)
+		}
+	}
+	if operationSlot(&OpenAPIPathItem{}, e.Method) == nil {
+		return fmt.Errorf("%s %s: unsupported method", e.Method, e.Path)
+	}
+	var types []reflect.Type
+	if e.Params != nil {
+		fields, err := paramFields(reflect.TypeOf(e.Params))
+		if err != nil {
+			return fmt.Errorf("%s %s: %w", e.Method, e.Path, err)
+		}
+		for _, f := range fields {
+			types = append(types, f.Type)
+		}
+	}
+	if e.Request != nil {
+		types = append(types, reflect.TypeOf(e.Request))
+	}
+	for _, body := range e.Responses {
+		if body != nil {
+			types = append(types, reflect.TypeOf(body))
+		}
+	}
+	for _, t := range types {
+		if unsupported := b.reflector.unsupportedType(t, map[reflect.Type]bool{}); unsupported != nil {
+			return fmt.Errorf("%s %s: type %s cannot be described by a schema", e.Method, e.Path, unsupported)
+		}
+	}
+	b.endpoints = append(b.endpoints, e)
+	return nil
+}
+
+// Document returns the OpenAPI document for all endpoints added so far.
+//
+// The endpoints are reflected together, so that distinct types sharing a name
+// get distinct components, as in Reflect.
+func (b *OpenAPIBuilder) Document(info OpenAPIInfo) *OpenAPIDocument {
+	var build *openAPIBuild
+	_, _ = b.reflector.reflectNamed(func(r *Reflector) error {
+		build = newOpenAPIBuild(r)
+		for _, e := range b.endpoints {
+			build.add(e)
+		}
+		return nil
+	})
+	doc := &OpenAPIDocument{
+		OpenAPI: OpenAPIVersion,
+		Info:    info,
+		Paths:   build.paths,
+	}
+	for _, name := range sortedKeys(build.definitions) {
+		build.definitions[name].walk(componentsRef(build.definitions))
+	}
+	if len(build.definitions) > 0 {
+		doc.Components = &OpenAPIComponents{Schemas: build.definitions}
+	}
+	return doc
+}
+
+// openAPIBuild holds the paths and shared definitions reflected from
+// endpoints.
+type openAPIBuild struct {
+	reflector   *Reflector
+	definitions Definitions
+	paths       map[string]*OpenAPIPathItem
+}
+
+func newOpenAPIBuild(r *Reflector) *openAPIBuild {
+	return &openAPIBuild{
+		reflector:   r,
+		definitions: Definitions{},
+		paths:       map[string]*OpenAPIPathItem{},
+	}
+}
+
+// add reflects e, which was checked by Add.
+func (b *openAPIBuild) add(e Endpoint) {
+	op := &OpenAPIOperation{
+		OperationID: e.OperationID,
+		Summary:     e.Summary,
+		Description: e.Description,
+		Tags:        e.Tags,
+		Responses:   map[string]*OpenAPIResponse{},
+	}
+	if e.Params != nil {
+		op.Parameters = b.reflectParams(reflect.TypeOf(e.Params))
+	}
+	if e.Request != nil {
+		op.RequestBody = &OpenAPIRequestBody{
+			Required: true,
+			Content:  b.content(reflect.TypeOf(e.Request)),
+		}
+	}
+	for code, body := range e.Responses {
+		resp := &OpenAPIResponse{Description: http.StatusText(code)}
+		if body != nil {
+			resp.Content = b.content(reflect.TypeOf(body))
+		}
+		op.Responses[strconv.Itoa(code)] = resp
+	}
+
+	item, ok := b.paths[e.Path]
+	if !ok {
+		item = &OpenAPIPathItem{}
+	}
+	*operationSlot(item, e.Method) = op
+	b.paths[e.Path] = item
+}
+
+// operationSlot returns the field of item holding the operation for method,
+// or nil if OpenAPI has none.
+func operationSlot(item *OpenAPIPathItem, method string) **OpenAPIOperation {
+	switch strings.ToUpper(method) {
+	case http.MethodGet:
+		return &item.Get
+	case http.MethodPut:
+		return &item.Put
+	case http.MethodPost:
+		return &item.Post
+	case http.MethodDelete:
+		return &item.Delete
+	case http.MethodOptions:
+		return &item.Options
+	case http.MethodHead:
+		return &item.Head
+	case http.MethodPatch:
+		return &item.Patch
+	case http.MethodTrace:
+		return &item.Trace
+	}
+	return nil
+}
+
+func (b *openAPIBuild) content(t reflect.Type) map[string]*OpenAPIMediaType {
+	schema := b.reflector.reflectTypeToSchema(b.definitions, t)
+	schema.walk(componentsRef(b.definitions))
+	return map[string]*OpenAPIMediaType{
+		"application/json": {Schema: schema},
+	}
+}
+
+func (b *openAPIBuild) reflectParams(t reflect.Type) []*OpenAPIParameter {
+	// The params were checked by Add.
+	fields, _ := paramFields(t)
+	var params []*OpenAPIParameter
+	for _, f := range fields {
+		in, name := paramLocation(f)
+		schema := b.reflector.reflectTypeToSchema(b.definitions, f.Type)
+		schema.structKeywordsFromTags(f, &Type{}, name)
+		schema.walk(componentsRef(b.definitions))
+		jsonSchemaTags := strings.Split(f.Tag.Get("jsonschema"), ",")
+		params = append(params, &OpenAPIParameter{
+			Name:        name,
+			In:          in,
+			Description: schema.Description,
+			Required:    in == "path" || requiredFromJSONSchemaTags(jsonSchemaTags),
+			Schema:      schema,
+		})
+	}
+	return params
+}
+
+// paramFields returns the parameter fields of t, a params struct, including
+// those of embedded structs.
+func paramFields(t reflect.Type) ([]reflect.StructField, error) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("params must be a struct, not %s", t)
+	}
+	var fields []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if in, _ := paramLocation(f); in != "" {
+			fields = append(fields, f)
+		} else if f.Anonymous {
+			embedded, err := paramFields(f.Type)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, embedded...)
+		}
+	}
+	return fields, nil
+}
+
+// paramLocation returns where a parameter field is sent and under what name.
+func paramLocation(f reflect.StructField) (string, string) {
+	if !f.Anonymous && f.PkgPath != "" {
+		return "", ""
+	}
+	for _, in := range paramLocations {
+		tag, ok := f.Tag.Lookup(in)
+		if !ok {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if name == "-" {
+			return "", ""
+		}
+		if name == "" {
+			name = f.Name
+		}
+		return in, name
+	}
+	return "", ""
+}
+
+// componentsRef returns a function rewriting a draft-04 schema for the
+// components section of OpenAPI 3.1 and OpenRPC documents, whose schemas
+// follow later drafts: references point into the components, boolean
+// exclusive bounds become numeric ones, and nested definitions are moved
+// into definitions, the shared components.
+func componentsRef(definitions Definitions) func(*Type) {
+	var rewrite func(*Type)
+	rewrite = func(t *Type) {
+		t.Version = ""
+		if strings.HasPrefix(t.Ref, "#/definitions/") {
+			t.Ref = "#/components/schemas/" + strings.TrimPrefix(t.Ref, "#/definitions/")
+		}
+		numericExclusiveBound(t, &t.ExclusiveMinimum, &t.Minimum, "minimum", "exclusiveMinimum")
+		numericExclusiveBound(t, &t.ExclusiveMaximum, &t.Maximum, "maximum", "exclusiveMaximum")
+		for _, name := range sortedKeys(t.Definitions) {
+			if _, ok := definitions[name]; !ok {
+				definitions[name] = t.Definitions[name]
+				definitions[name].walk(rewrite)
+			}
+		}
+		t.Definitions = nil
+	}
+	return rewrite
+}
+
+// numericExclusiveBound replaces the boolean exclusive flag of a bound, as in
+// draft-04, with the exclusive bound itself, as in later drafts.
+func numericExclusiveBound(t *Type, exclusive *bool, bound *int, name, exclusiveName string) {
+	if !*exclusive {
+		return
+	}
+	value, _ := extraNumber(t, name)
+	if *bound != 0 {
+		value = float64(*bound)
+	}
+	*exclusive, *bound = false, 0
+	delete(t.Extras, name)
+	if t.Extras == nil {
+		t.Extras = map[string]interface{}{}
+	}
+	t.Extras[exclusiveName] = value
+}
@@ -0,0 +1,126 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type UserParams struct {
+	ID      int    `path:"id"`
+	Verbose bool   `query:"verbose" jsonschema_description:"include all fields"`
+	Token   string `header:"X-Token" jsonschema:"required"`
+	Ignored string `query:"-"`
+}
+
+type UpdateUserParams struct {
+	UserParams
+	DryRun bool `query:"dry_run"`
+}
+
+type UpdateUserRequest struct {
+	Name  string `json:"name" jsonschema:"minLength=1"`
+	Email string `json:"email,omitempty" jsonschema:"format=email"`
+}
+
+type UserResponse struct {
+	ID          int             `json:"id"`
+	Name        string          `json:"name"`
+	Grandfather GrandfatherType `json:"grand"`
+}
+
+func TestOpenAPIBuilder(t *testing.T) {
+	b := NewOpenAPIBuilder(nil)
+	require.NoError(t, b.Add(Endpoint{
+		Method:      "GET",
+		Path:        "/users/{id}",
+		OperationID: "getUser",
+		Params:      &UserParams{},
+		Responses:   map[int]interface{}{200: &UserResponse{}, 404: nil},
+	}))
+	require.NoError(t, b.Add(Endpoint{
+		Method:      "PUT",
+		Path:        "/users/{id}",
+		OperationID: "updateUser",
+		Tags:        []string{"users"},
+		Params:      UpdateUserParams{},
+		Request:     &UpdateUserRequest{},
+		Responses:   map[int]interface{}{200: &UserResponse{}},
+	}))
+	require.Error(t, b.Add(Endpoint{Method: "GET", Path: "/users/{id}"}))
+	require.Error(t, b.Add(Endpoint{Method: "GET", Path: "/broken", Params: 1}))
+
+	doc := b.Document(OpenAPIInfo{Title: "Users", Version: "1.0.0"})
+	actualJSON, err := json.MarshalIndent(doc, "", "  ")
+	require.NoError(t, err)
+	expectedJSON, err := ioutil.ReadFile("fixtures/openapi.json")
+	require.NoError(t, err)
+	require.JSONEq(t, string(expectedJSON), string(actualJSON))
+}
+
+type OpenAPIBounded struct {
+	Age  int          `json:"age" jsonschema:"minimum=18,exclusiveMinimum=true,maximum=120,exclusiveMaximum=true"`
+	Tree OpenAPITree  `json:"tree"`
+	Leaf *OpenAPILeaf `json:"leaf,omitempty"`
+}
+
+type OpenAPILeaf struct {
+	Name string `json:"name"`
+}
+
+type OpenAPITree struct{}
+
+func (OpenAPITree) JSONSchemaType() *Type {
+	return &Type{
+		Ref:         "#/definitions/OpenAPINode",
+		Definitions: Definitions{"OpenAPINode": {Type: "object", Minimum: 1, ExclusiveMinimum: true}},
+	}
+}
+
+func TestOpenAPIBuilderSchemaDialect(t *testing.T) {
+	b := NewOpenAPIBuilder(nil)
+	require.NoError(t, b.Add(Endpoint{Method: "POST", Path: "/bounded", Request: &OpenAPIBounded{}}))
+	doc := b.Document(OpenAPIInfo{Title: "Bounded", Version: "1.0.0"})
+	actualJSON, err := json.Marshal(doc.Components.Schemas)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"OpenAPIBounded": {
+			"type": "object",
+			"required": ["age", "tree"],
+			"properties": {
+				"age": {"type": "integer", "exclusiveMinimum": 18, "exclusiveMaximum": 120},
+				"tree": {"$ref": "#/components/schemas/OpenAPITree"},
+				"leaf": {"$ref": "#/components/schemas/OpenAPILeaf"}
+			},
+			"additionalProperties": false
+		},
+		"OpenAPILeaf": {
+			"type": "object",
+			"required": ["name"],
+			"properties": {"name": {"type": "string"}},
+			"additionalProperties": false
+		},
+		"OpenAPINode": {"type": "object", "exclusiveMinimum": 1},
+		"OpenAPITree": {"$ref": "#/components/schemas/OpenAPINode"}
+	}`, string(actualJSON))
+}
+
+func TestOpenAPIBuilderUnsupportedTypes(t *testing.T) {
+	type events struct {
+		Updates chan string `json:"updates"`
+	}
+	type callbackParams struct {
+		Callback func() `query:"callback"`
+	}
+	b := NewOpenAPIBuilder(nil)
+	require.EqualError(t, b.Add(Endpoint{Method: "POST", Path: "/events", Request: &events{}}),
+		"POST /events: type chan string cannot be described by a schema")
+	require.EqualError(t, b.Add(Endpoint{Method: "GET", Path: "/events", Responses: map[int]interface{}{200: []events{}}}),
+		"GET /events: type chan string cannot be described by a schema")
+	require.EqualError(t, b.Add(Endpoint{Method: "GET", Path: "/callback", Params: callbackParams{}}),
+		"GET /callback: type func() cannot be described by a schema")
+	require.EqualError(t, b.Add(Endpoint{Method: "CONNECT", Path: "/events"}), "CONNECT /events: unsupported method")
+	require.Nil(t, b.Document(OpenAPIInfo{Title: "Events", Version: "1.0.0"}).Components)
+}
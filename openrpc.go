@@ -0,0 +1,244 @@
+package jsonschema
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+)
+
+// OpenRPCVersion is the OpenRPC specification version emitted by OpenRPCBuilder.
+var OpenRPCVersion = "1.2.6"
+
+// OpenRPCDocument is the root of an OpenRPC document.
+type OpenRPCDocument struct {
+	OpenRPC    string             `json:"openrpc"`
+	Info       OpenRPCInfo        `json:"info"`
+	Methods    []*OpenRPCMethod   `json:"methods"`
+	Components *OpenRPCComponents `json:"components,omitempty"`
+}
+
+// OpenRPCInfo provides metadata about the API.
+type OpenRPCInfo struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// OpenRPCComponents holds the reusable schemas referenced from methods.
+type OpenRPCComponents struct {
+	Schemas Definitions `json:"schemas,omitempty"`
+}
+
+// OpenRPCMethod describes a single JSON-RPC method.
+type OpenRPCMethod struct {
+	Name           string                      `json:"name"`
+	Summary        string                      `json:"summary,omitempty"`
+	Description    string                      `json:"description,omitempty"`
+	ParamStructure string                      `json:"paramStructure,omitempty"`
+	Params         []*OpenRPCContentDescriptor `json:"params"`
+	Result         *OpenRPCContentDescriptor   `json:"result"`
+}
+
+// OpenRPCContentDescriptor describes a method parameter or result.
+type OpenRPCContentDescriptor struct {
+	Name     string `json:"name"`
+	Required bool   `json:"required,omitempty"`
+	Schema   *Type  `json:"schema"`
+}
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// OpenRPCBuilder collects Go functions and methods into an OpenRPC document,
+// sharing definitions between all of them.
+//
+// Leading context.Context arguments and trailing error results are ignored.
+// Every remaining argument becomes a positional parameter, and a single
+// remaining result becomes the method result.
+type OpenRPCBuilder struct {
+	reflector  *Reflector
+	signatures []*openRPCSignature
+}
+
+// NewOpenRPCBuilder creates a builder reflecting types with r.
+// If r is nil the default Reflector is used.
+func NewOpenRPCBuilder(r *Reflector) *OpenRPCBuilder {
+	if r == nil {
+		r = &Reflector{}
+	}
+	return &OpenRPCBuilder{reflector: r}
+}
+
+// AddService adds every exported method of svc.
+// Method names are prefixed with namespace and a "." if namespace is not empty.
+// If any method cannot be described, none are added.
+func (b *OpenRPCBuilder) AddService(namespace string, svc interface{}) error {
+	t := reflect.TypeOf(svc)
+	var sigs []*openRPCSignature
+	for i := 0; i < t.NumMethod(); i++ {
+		m := t.Method(i)
+		name := m.Name
+		if namespace != "" {
+			name = namespace + "." + name
+		}
+		// Skip the receiver.
+		sig, err := b.signature(name, m.Type, 1)
+		if err != nil {
+			return err
+		}
+		sigs = append(sigs, sig)
+	}
+	b.signatures = append(b.signatures, sigs...)
+	return nil
+}
+
+// AddFunc adds the function fn as the method name.
+func (b *OpenRPCBuilder) AddFunc(name string, fn interface{}) error {
+	t := reflect.TypeOf(fn)
+	if t == nil || t.Kind() != reflect.Func {
+		return fmt.Errorf("%s: expected a function, not %T", name, fn)
+	}
+	sig, err := b.signature(name, t, 0)
+	if err != nil {
+		return err
+	}
+	b.signatures = append(b.signatures, sig)
+	return nil
+}
+
+// Document returns the OpenRPC document for all methods added so far.
+//
+// The methods are reflected together, so that distinct types sharing a name
+// get distinct components, as in Reflect.
+func (b *OpenRPCBuilder) Document(info OpenRPCInfo) *OpenRPCDocument {
+	var build *openRPCBuild
+	_, _ = b.reflector.reflectNamed(func(r *Reflector) error {
+		build = &openRPCBuild{reflector: r, definitions: Definitions{}, methods: []*OpenRPCMethod{}}
+		for _, sig := range b.signatures {
+			build.add(sig)
+		}
+		return nil
+	})
+	doc := &OpenRPCDocument{
+		OpenRPC: OpenRPCVersion,
+		Info:    info,
+		Methods: build.methods,
+	}
+	for _, name := range sortedKeys(build.definitions) {
+		build.definitions[name].walk(componentsRef(build.definitions))
+	}
+	if len(build.definitions) > 0 {
+		doc.Components = &OpenRPCComponents{Schemas: build.definitions}
+	}
+	return doc
+}
+
+// openRPCSignature holds the parameter and result types of a method.
+type openRPCSignature struct {
+	name   string
+	params []reflect.Type
+	result reflect.Type
+}
+
+// signature checks that the function type t can be described as the method
+// name, skipping its first skip arguments.
+func (b *OpenRPCBuilder) signature(name string, t reflect.Type, skip int) (*openRPCSignature, error) {
+	sig := &openRPCSignature{name: name}
+	for i := skip; i < t.NumIn(); i++ {
+		in := t.In(i)
+		if i == skip && in == contextType {
+			continue
+		}
+		if t.IsVariadic() && i == t.NumIn()-1 {
+			return nil, fmt.Errorf("%s: variadic arguments are not supported", name)
+		}
+		sig.params = append(sig.params, in)
+	}
+
+	var results []reflect.Type
+	for i := 0; i < t.NumOut(); i++ {
+		results = append(results, t.Out(i))
+	}
+	if len(results) > 0 && results[len(results)-1] == errorType {
+		results = results[:len(results)-1]
+	}
+	switch len(results) {
+	case 0:
+	case 1:
+		sig.result = results[0]
+	default:
+		return nil, fmt.Errorf("%s: expected at most one result besides error, got %d", name, len(results))
+	}
+
+	for _, typ := range append(sig.params, results...) {
+		if unsupported := b.reflector.unsupportedType(typ, map[reflect.Type]bool{}); unsupported != nil {
+			return nil, fmt.Errorf("%s: type %s cannot be described by a schema", name, unsupported)
+		}
+	}
+	return sig, nil
+}
+
+// openRPCBuild holds the methods and shared definitions reflected from
+// signatures.
+type openRPCBuild struct {
+	reflector   *Reflector
+	definitions Definitions
+	methods     []*OpenRPCMethod
+}
+
+func (b *openRPCBuild) add(sig *openRPCSignature) {
+	method := &OpenRPCMethod{
+		Name:           sig.name,
+		ParamStructure: "by-position",
+		Params:         []*OpenRPCContentDescriptor{},
+	}
+	names := map[string]bool{}
+	for i, in := range sig.params {
+		method.Params = append(method.Params, &OpenRPCContentDescriptor{
+			Name:     paramName(in, "param"+strconv.Itoa(i), names),
+			Required: in.Kind() != reflect.Ptr,
+			Schema:   b.schema(in),
+		})
+	}
+	// OpenRPC requires a result, so methods returning only an error get a
+	// null one.
+	method.Result = &OpenRPCContentDescriptor{Name: "result", Schema: &Type{Type: "null"}}
+	if sig.result != nil {
+		method.Result = &OpenRPCContentDescriptor{
+			Name:   paramName(sig.result, "result", map[string]bool{}),
+			Schema: b.schema(sig.result),
+		}
+	}
+	b.methods = append(b.methods, method)
+}
+
+func (b *openRPCBuild) schema(t reflect.Type) *Type {
+	schema := b.reflector.reflectTypeToSchema(b.definitions, t)
+	schema.walk(componentsRef(b.definitions))
+	return schema
+}
+
+// paramName derives a unique descriptor name from a named Go type, as
+// reflection does not expose argument names. Unnamed and predeclared types
+// use fallback instead.
+func paramName(t reflect.Type, fallback string, used map[string]bool) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	name := fallback
+	if t.Name() != "" && t.PkgPath() != "" {
+		r, size := utf8.DecodeRuneInString(t.Name())
+		name = string(unicode.ToLower(r)) + t.Name()[size:]
+	}
+	unique := name
+	for i := 2; used[unique]; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	used[unique] = true
+	return unique
+}
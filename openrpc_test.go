@@ -0,0 +1,83 @@
+package jsonschema
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type CreateUserRequest struct {
+	Name        string          `json:"name"`
+	Grandfather GrandfatherType `json:"grand"`
+}
+
+type CreateUserResponse struct {
+	ID          int             `json:"id"`
+	Grandfather GrandfatherType `json:"grand"`
+}
+
+type UserService struct{}
+
+func (s *UserService) Create(ctx context.Context, req *CreateUserRequest) (*CreateUserResponse, error) {
+	return nil, nil
+}
+
+func (s *UserService) Delete(ctx context.Context, id int) error {
+	return nil
+}
+
+type BrokenService struct{}
+
+func (BrokenService) Split() (int, int) { return 0, 0 }
+
+func TestOpenRPCBuilder(t *testing.T) {
+	b := NewOpenRPCBuilder(nil)
+	require.NoError(t, b.AddService("users", &UserService{}))
+	require.NoError(t, b.AddFunc("ping", func(msg string, count int) string { return msg }))
+	require.Error(t, b.AddFunc("notAFunc", 1))
+	require.Error(t, NewOpenRPCBuilder(nil).AddService("", BrokenService{}))
+
+	doc := b.Document(OpenRPCInfo{Title: "Users", Version: "1.0.0"})
+	actualJSON, err := json.MarshalIndent(doc, "", "  ")
+	require.NoError(t, err)
+	expectedJSON, err := ioutil.ReadFile("fixtures/openrpc.json")
+	require.NoError(t, err)
+	require.JSONEq(t, string(expectedJSON), string(actualJSON))
+}
+
+type EventRequest struct {
+	Topic   string       `json:"topic"`
+	Handler func(string) `json:"-"`
+}
+
+type EventService struct{}
+
+func (s *EventService) Publish(req EventRequest) error { return nil }
+
+func (s *EventService) Subscribe(topic string, events chan<- string) error { return nil }
+
+func (s *EventService) Watch(req *struct {
+	OnEvent func(string) `json:"onEvent"`
+}) error {
+	return nil
+}
+
+func TestOpenRPCBuilderUnsupportedTypes(t *testing.T) {
+	b := NewOpenRPCBuilder(nil)
+	err := b.AddService("events", &EventService{})
+	require.EqualError(t, err, "events.Subscribe: type chan<- string cannot be described by a schema")
+	err = b.AddFunc("watch", (&EventService{}).Watch)
+	require.EqualError(t, err, "watch: type func(string) cannot be described by a schema")
+	err = b.AddFunc("callback", func(cb func(string)) error { return nil })
+	require.EqualError(t, err, "callback: type func(string) cannot be described by a schema")
+
+	doc := b.Document(OpenRPCInfo{Title: "events", Version: "1"})
+	require.Empty(t, doc.Methods, "no methods of a failed service are added")
+	require.Nil(t, doc.Components)
+
+	require.NoError(t, b.AddFunc("publish", (&EventService{}).Publish))
+	require.Len(t, b.Document(OpenRPCInfo{}).Methods, 1)
+}
@@ -0,0 +1,52 @@
+package jsonschema
+
+// Optimize returns a copy of s without the definitions that are unreachable
+// from its root type, and with structurally equal definitions merged into
+// one, keeping the name that sorts first. References to the merged
+// definitions are rewritten.
+//
+// Definitions are equal when their JSON encodings are, ignoring the order of
+// object keys. Definitions that only differ in which of two equal definitions
+// they refer to are merged too.
+func Optimize(s *Schema) *Schema {
+	out := s.clone()
+	out.pruneDefinitions()
+	for {
+		merged := map[string]string{}
+		seen := map[string]string{}
+		for _, name := range sortedKeys(out.Definitions) {
+			key := canonicalJSON(out.Definitions[name])
+			if first, ok := seen[key]; ok {
+				merged[name] = first
+				delete(out.Definitions, name)
+				continue
+			}
+			seen[key] = name
+		}
+		if len(merged) == 0 {
+			break
+		}
+		out.walk(func(t *Type) {
+			if name, ok := definitionName(t.Ref); ok {
+				if target, ok := merged[name]; ok {
+					t.Ref = "#/definitions/" + target
+				}
+			}
+		})
+	}
+	return out
+}
+
+// pruneDefinitions removes the definitions of s that are unreachable from its
+// root type.
+func (s *Schema) pruneDefinitions() {
+	reachable := s.reachableDefinitions()
+	for name := range s.Definitions {
+		if !reachable[name] {
+			delete(s.Definitions, name)
+		}
+	}
+	if len(s.Definitions) == 0 {
+		s.Definitions = nil
+	}
+}
@@ -0,0 +1,76 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type OptimizeAddress struct {
+	City string `json:"city"`
+}
+
+type OptimizeLocation struct {
+	City string `json:"city"`
+}
+
+type OptimizeHome struct {
+	Address OptimizeAddress `json:"address"`
+}
+
+type OptimizeWork struct {
+	Address OptimizeLocation `json:"address"`
+}
+
+type OptimizePerson struct {
+	Home OptimizeHome `json:"home"`
+	Work OptimizeWork `json:"work"`
+}
+
+func TestOptimize(t *testing.T) {
+	s := Reflect(&OptimizePerson{})
+	s.Definitions["Unused"] = &Type{Type: "string"}
+	before, err := json.Marshal(s)
+	require.NoError(t, err)
+
+	b, err := json.Marshal(Optimize(s))
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"$schema": "http://json-schema.org/draft-04/schema#",
+		"$ref": "#/definitions/OptimizePerson",
+		"definitions": {
+			"OptimizeAddress": {
+				"type": "object",
+				"required": ["city"],
+				"properties": {"city": {"type": "string"}},
+				"additionalProperties": false
+			},
+			"OptimizeHome": {
+				"type": "object",
+				"required": ["address"],
+				"properties": {"address": {"$schema": "http://json-schema.org/draft-04/schema#", "$ref": "#/definitions/OptimizeAddress"}},
+				"additionalProperties": false
+			},
+			"OptimizePerson": {
+				"type": "object",
+				"required": ["home", "work"],
+				"properties": {
+					"home": {"$schema": "http://json-schema.org/draft-04/schema#", "$ref": "#/definitions/OptimizeHome"},
+					"work": {"$schema": "http://json-schema.org/draft-04/schema#", "$ref": "#/definitions/OptimizeHome"}
+				},
+				"additionalProperties": false
+			}
+		}
+	}`, string(b))
+
+	after, err := json.Marshal(s)
+	require.NoError(t, err)
+	require.Equal(t, string(before), string(after), "the original schema is not modified")
+}
+
+func TestOptimizeWithoutDefinitions(t *testing.T) {
+	s := Optimize(&Schema{Type: &Type{Type: "string"}, Definitions: Definitions{"Unused": {Type: "integer"}}})
+	require.Nil(t, s.Definitions)
+	require.Equal(t, "string", s.Type.Type)
+}
@@ -0,0 +1,422 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"math/rand"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// QuickT is the part of testing.TB used by Quick, so that the package does
+// not depend on the testing package.
+type QuickT interface {
+	Helper()
+	Fatalf(format string, args ...interface{})
+}
+
+// QuickConfig controls how Quick checks a handler.
+type QuickConfig struct {
+	// Reflector reflects the schema of the checked value. Defaults to an
+	// empty Reflector.
+	Reflector *Reflector
+	// Count is the number of documents generated. Defaults to 100.
+	Count int
+	// Seed seeds generation, so that runs are reproducible.
+	Seed int64
+	// ValidOnly only checks that valid documents are accepted, for handlers
+	// that are not expected to enforce every constraint of the schema.
+	ValidOnly bool
+}
+
+// Quick checks fn against documents generated from the schema of v, in the
+// spirit of testing/quick, using the default QuickConfig.
+func Quick(t QuickT, v interface{}, fn func(doc []byte) error) {
+	t.Helper()
+	(&QuickConfig{}).Check(t, v, fn)
+}
+
+// Check feeds fn valid documents generated from the schema of v, along with
+// variants of them at the boundaries of the schema's constraints: values at
+// and just past their minimum and maximum, strings and arrays of the shortest
+// and longest allowed lengths, wrong types, values outside an enum and missing
+// required properties.
+//
+// fn must return nil for valid documents and an error for invalid ones. A
+// rejected valid document is shrunk, by dropping optional properties and
+// items and simplifying values, before it is reported. The first failure
+// stops the test.
+func (c *QuickConfig) Check(t QuickT, v interface{}, fn func(doc []byte) error) {
+	t.Helper()
+	r := c.Reflector
+	if r == nil {
+		r = &Reflector{}
+	}
+	count := c.Count
+	if count <= 0 {
+		count = 100
+	}
+	schema := r.Reflect(v)
+	q := &quickChecker{definitions: schema.Definitions, fn: fn}
+	src := rand.NewSource(c.Seed)
+	for i := 0; i < count; i++ {
+		doc, err := Generate(schema, src)
+		if err != nil {
+			t.Fatalf("jsonschema: cannot generate documents: %v", err)
+			return
+		}
+		if err := q.call(doc); err != nil {
+			doc, err = q.shrink(schema.Type, doc, err)
+			t.Fatalf("jsonschema: valid document rejected: %v\n%s", err, quickJSON(doc))
+			return
+		}
+		for _, variant := range q.variants(schema.Type, doc, "") {
+			err := q.call(variant.value)
+			switch {
+			case variant.valid && err != nil:
+				doc, err = q.shrink(schema.Type, variant.value, err)
+				t.Fatalf("jsonschema: valid document (%s) rejected: %v\n%s", variant.desc, err, quickJSON(doc))
+				return
+			case !variant.valid && err == nil && !c.ValidOnly:
+				t.Fatalf("jsonschema: invalid document (%s) accepted:\n%s", variant.desc, quickJSON(variant.value))
+				return
+			}
+		}
+	}
+}
+
+type quickChecker struct {
+	definitions Definitions
+	fn          func(doc []byte) error
+}
+
+func (q *quickChecker) call(v interface{}) error {
+	doc, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return q.fn(doc)
+}
+
+// quickVariant is a document derived from a generated one, either just inside
+// or just outside the constraints of its schema.
+type quickVariant struct {
+	value interface{}
+	valid bool
+	desc  string
+}
+
+// variants returns the boundary variants of v, a valid instance of t. Schemas
+// that can only be told apart by their alternatives, such as oneOf, are left
+// alone.
+func (q *quickChecker) variants(t *Type, v interface{}, path string) []quickVariant {
+	t = q.definitions.resolve(t)
+	if t == nil || t.Type == "" || v == nil {
+		return nil
+	}
+	var out []quickVariant
+	add := func(value interface{}, valid bool, msg string) {
+		out = append(out, quickVariant{value, valid, generatePath(path) + ": " + msg})
+	}
+	add(quickWrongType[t.Type], false, "wrong type")
+	if len(t.Enum) > 0 {
+		add(quickNotInEnum(t.Enum), false, "value not in enum")
+		return out
+	}
+
+	switch t.Type {
+	case "integer":
+		min, max := integerBounds(t)
+		if t.Minimum != 0 || t.ExclusiveMinimum {
+			if t.MultipleOf == 0 {
+				add(min, true, "minimum")
+			}
+			add(min-1, false, "below minimum")
+		}
+		if t.Maximum != 0 || t.ExclusiveMaximum {
+			if t.MultipleOf == 0 {
+				add(max, true, "maximum")
+			}
+			add(max+1, false, "above maximum")
+		}
+	case "number":
+		if t.Minimum != 0 || t.ExclusiveMinimum {
+			if t.ExclusiveMinimum {
+				add(float64(t.Minimum), false, "exclusive minimum")
+			} else {
+				add(float64(t.Minimum), t.MultipleOf == 0, "minimum")
+				add(float64(t.Minimum)-0.5, false, "below minimum")
+			}
+		}
+		if t.Maximum != 0 || t.ExclusiveMaximum {
+			if t.ExclusiveMaximum {
+				add(float64(t.Maximum), false, "exclusive maximum")
+			} else {
+				add(float64(t.Maximum), t.MultipleOf == 0, "maximum")
+				add(float64(t.Maximum)+0.5, false, "above maximum")
+			}
+		}
+	case "string":
+		plain := t.Pattern == "" && t.Format == "" && t.Media == nil
+		if t.MinLength > 0 {
+			if plain {
+				add(strings.Repeat("a", t.MinLength), true, "minLength")
+			}
+			add(strings.Repeat("a", t.MinLength-1), false, "below minLength")
+		}
+		if t.MaxLength > 0 {
+			if plain {
+				add(strings.Repeat("a", t.MaxLength), true, "maxLength")
+			}
+			add(strings.Repeat("a", t.MaxLength+1), false, "above maxLength")
+		}
+		if t.Pattern != "" {
+			if s, ok := quickNotMatching(t.Pattern, t.MinLength); ok {
+				add(s, false, "does not match pattern")
+			}
+		}
+		if t.Format != "" && !validFormat(t.Format, "!") {
+			add("!", false, "invalid "+t.Format)
+		}
+	case "array":
+		items, _ := v.([]interface{})
+		if t.MinItems > 0 && len(items) >= t.MinItems {
+			add(items[:t.MinItems:t.MinItems], true, "minItems")
+			add(items[:t.MinItems-1:t.MinItems-1], false, "below minItems")
+		}
+		if t.MaxItems > 0 && len(items) > 0 {
+			grown := append([]interface{}{}, items...)
+			for len(grown) <= t.MaxItems {
+				grown = append(grown, items[len(grown)%len(items)])
+			}
+			add(grown, false, "above maxItems")
+		}
+		if t.UniqueItems && len(items) > 0 {
+			add(append(append([]interface{}{}, items...), items[0]), false, "duplicate items")
+		}
+		for i, item := range items {
+			for _, variant := range q.variants(t.Items, item, path+"/"+strconv.Itoa(i)) {
+				replaced := append([]interface{}{}, items...)
+				replaced[i] = variant.value
+				out = append(out, quickVariant{replaced, variant.valid, variant.desc})
+			}
+		}
+	case "object":
+		obj, _ := v.(map[string]interface{})
+		for _, key := range t.Required {
+			if _, ok := obj[key]; ok {
+				add(quickWithout(obj, key), false, "missing required property "+strconv.Quote(key))
+			}
+		}
+		if string(t.AdditionalProperties) == "false" && len(t.PatternProperties) == 0 {
+			key := "unexpected"
+			for t.hasProperty(key) {
+				key += "_"
+			}
+			add(quickWith(obj, key, true), false, "unexpected property "+strconv.Quote(key))
+		}
+		if t.Properties == nil {
+			break
+		}
+		for _, key := range t.Properties.Keys() {
+			value, ok := obj[key]
+			p, isType := t.Properties.Get(key)
+			if !ok || !isType {
+				continue
+			}
+			sub, _ := p.(*Type)
+			if sub == nil {
+				continue
+			}
+			for _, variant := range q.variants(sub, value, path+"/"+pointerEscape(key)) {
+				out = append(out, quickVariant{quickWith(obj, key, variant.value), variant.valid, variant.desc})
+			}
+		}
+	}
+	return out
+}
+
+// shrink simplifies v, a valid instance of t rejected by the handler with
+// err, for as long as the handler keeps rejecting it.
+func (q *quickChecker) shrink(t *Type, v interface{}, err error) (interface{}, error) {
+	for step := 0; step < 1000; step++ {
+		shrunk := false
+		for _, candidate := range q.shrinks(t, v) {
+			if cerr := q.call(candidate); cerr != nil {
+				v, err, shrunk = candidate, cerr, true
+				break
+			}
+		}
+		if !shrunk {
+			break
+		}
+	}
+	return v, err
+}
+
+// shrinks returns valid instances of t that are simpler than v.
+func (q *quickChecker) shrinks(t *Type, v interface{}) []interface{} {
+	t = q.definitions.resolve(t)
+	if t == nil || t.Type == "" || len(t.Enum) > 0 || v == nil {
+		return nil
+	}
+	var out []interface{}
+	switch t.Type {
+	case "integer":
+		n, ok := v.(int64)
+		if !ok || t.MultipleOf != 0 {
+			break
+		}
+		min, max := integerBounds(t)
+		target := int64(0)
+		if target < min {
+			target = min
+		} else if target > max {
+			target = max
+		}
+		if n != target {
+			out = append(out, target, target+(n-target)/2)
+		}
+	case "number":
+		n, ok := v.(float64)
+		if ok && t.MultipleOf == 0 && t.Minimum == 0 && t.Maximum == 0 && n != 0 {
+			out = append(out, 0.0)
+		}
+	case "string":
+		s, ok := v.(string)
+		if ok && t.Pattern == "" && t.Format == "" && t.Media == nil && len(s) > t.MinLength {
+			out = append(out, strings.Repeat("a", t.MinLength))
+			if half := len(s) / 2; half > t.MinLength {
+				out = append(out, s[:half])
+			}
+		}
+	case "array":
+		items, _ := v.([]interface{})
+		if len(items) > t.MinItems {
+			out = append(out, items[:len(items)-1:len(items)-1], append([]interface{}{}, items[1:]...))
+		}
+		for i, item := range items {
+			for _, candidate := range q.shrinks(t.Items, item) {
+				replaced := append([]interface{}{}, items...)
+				replaced[i] = candidate
+				out = append(out, replaced)
+			}
+		}
+	case "object":
+		obj, _ := v.(map[string]interface{})
+		keys := make([]string, 0, len(obj))
+		for key := range obj {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if !quickRequired(t, key) {
+				out = append(out, quickWithout(obj, key))
+			}
+		}
+		for _, key := range keys {
+			if !t.hasProperty(key) {
+				continue
+			}
+			p, _ := t.Properties.Get(key)
+			sub, _ := p.(*Type)
+			if sub == nil {
+				continue
+			}
+			for _, candidate := range q.shrinks(sub, obj[key]) {
+				out = append(out, quickWith(obj, key, candidate))
+			}
+		}
+	}
+	return out
+}
+
+var quickWrongType = map[string]interface{}{
+	"string":  false,
+	"integer": 1.5,
+	"number":  "0",
+	"boolean": "true",
+	"null":    false,
+	"array":   map[string]interface{}{},
+	"object":  []interface{}{},
+}
+
+func quickNotInEnum(enum []interface{}) interface{} {
+	for i := 0; ; i++ {
+		candidate := "invalid"
+		if i > 0 {
+			candidate += strconv.Itoa(i)
+		}
+		found := false
+		for _, v := range enum {
+			if reflect.DeepEqual(v, candidate) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return candidate
+		}
+	}
+}
+
+// quickNotMatching returns a string of at least minLength that does not match
+// pattern, if one of a few simple candidates qualifies.
+func quickNotMatching(pattern string, minLength int) (string, bool) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", false
+	}
+	for _, c := range []string{"!", " ", "0", "a", "A", "~"} {
+		s := strings.Repeat(c, minLength+1)
+		if !re.MatchString(s) {
+			return s, true
+		}
+	}
+	return "", false
+}
+
+// quickRequired reports whether key is required by t, either directly or by
+// one of its oneof_required groups.
+func quickRequired(t *Type, key string) bool {
+	if contains(t.Required, key) {
+		return true
+	}
+	for _, sub := range t.OneOf {
+		if contains(sub.Required, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// quickWith returns a copy of obj with key set to value.
+func quickWith(obj map[string]interface{}, key string, value interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(obj)+1)
+	for k, v := range obj {
+		out[k] = v
+	}
+	out[key] = value
+	return out
+}
+
+// quickWithout returns a copy of obj without key.
+func quickWithout(obj map[string]interface{}, key string) map[string]interface{} {
+	out := make(map[string]interface{}, len(obj))
+	for k, v := range obj {
+		if k != key {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+func quickJSON(v interface{}) string {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(b)
+}
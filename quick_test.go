@@ -0,0 +1,88 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type QuickUser struct {
+	Name string   `json:"name" jsonschema:"minLength=2,maxLength=10"`
+	Age  int      `json:"age" jsonschema:"minimum=18,maximum=99"`
+	Role string   `json:"role" jsonschema:"enum=admin,enum=user"`
+	Tags []string `json:"tags,omitempty"`
+}
+
+// quickT records the failure reported to it instead of failing the test.
+type quickT struct {
+	failure string
+}
+
+func (q *quickT) Helper() {}
+
+func (q *quickT) Fatalf(format string, args ...interface{}) {
+	q.failure = fmt.Sprintf(format, args...)
+}
+
+func validateQuickUser(maxAge, maxName int) func(doc []byte) error {
+	return func(doc []byte) error {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(doc, &fields); err != nil {
+			return err
+		}
+		for _, key := range []string{"name", "age", "role"} {
+			if _, ok := fields[key]; !ok {
+				return fmt.Errorf("missing %s", key)
+			}
+		}
+		var u QuickUser
+		dec := json.NewDecoder(bytes.NewReader(doc))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&u); err != nil {
+			return err
+		}
+		switch {
+		case len(u.Name) < 2 || len(u.Name) > maxName:
+			return errors.New("bad name")
+		case u.Age < 18 || u.Age > maxAge:
+			return errors.New("bad age")
+		case u.Role != "admin" && u.Role != "user":
+			return errors.New("bad role")
+		}
+		return nil
+	}
+}
+
+func TestQuick(t *testing.T) {
+	Quick(t, &QuickUser{}, validateQuickUser(99, 10))
+}
+
+func TestQuickInvalidAccepted(t *testing.T) {
+	qt := &quickT{}
+	Quick(qt, &QuickUser{}, validateQuickUser(100, 10))
+	require.Contains(t, qt.failure, "invalid document (/age: above maximum) accepted")
+}
+
+func TestQuickShrinksValidRejected(t *testing.T) {
+	qt := &quickT{}
+	Quick(qt, &QuickUser{}, validateQuickUser(99, 3))
+	require.Contains(t, qt.failure, "rejected: bad name")
+	// Optional properties are dropped and the age shrunk to its minimum.
+	require.NotContains(t, qt.failure, `"tags"`)
+	require.Contains(t, qt.failure, `"age": 18`)
+}
+
+func TestQuickValidOnly(t *testing.T) {
+	unmarshal := func(doc []byte) error {
+		return json.Unmarshal(doc, &QuickUser{})
+	}
+	(&QuickConfig{ValidOnly: true, Count: 20}).Check(t, &QuickUser{}, unmarshal)
+
+	qt := &quickT{}
+	(&QuickConfig{Count: 20}).Check(qt, &QuickUser{}, unmarshal)
+	require.Contains(t, qt.failure, "accepted")
+}
@@ -7,7 +7,9 @@
 package jsonschema
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"net"
 	"net/url"
 	"reflect"
@@ -52,6 +54,7 @@ var customStructGetFieldDocString = reflect.TypeOf((*customSchemaGetFieldDocStri
 type Type struct {
 	// RFC draft-wright-json-schema-00
 	Version string `json:"$schema,omitempty"` // section 6.1
+	ID      string `json:"$id,omitempty"`     // section 9.2
 	Ref     string `json:"$ref,omitempty"`    // section 7
 	// RFC draft-wright-json-schema-validation-00, section 5
 	MultipleOf           int                    `json:"multipleOf,omitempty"`           // section 5.1
@@ -128,6 +131,22 @@ type Reflector struct {
 	// are present
 	PreferYAMLSchema bool
 
+	// TagKeys lists the struct tags that name fields, such as "mapstructure",
+	// "toml" or "koanf", in order of precedence. The first tag present on a
+	// field gives its name, omits it with "-" and makes it optional with
+	// omitempty. Any of them embeds the field's struct with the inline option,
+	// or squash for mapstructure and koanf. Defaults to json then yaml, or
+	// only yaml with PreferYAMLSchema.
+	TagKeys []string
+
+	// ValidatorTags lists the struct tags holding go-playground/validator
+	// rules, such as "validate" or gin's "binding", to translate into
+	// keywords: required, min, max, len, gt, gte, lt, lte, oneof, unique,
+	// email, url, uuid and similar formats, and alpha, alphanum and numeric
+	// patterns. Keywords set by jsonschema tags take precedence. Reflect
+	// panics on rules it cannot translate, as it does on unsupported types.
+	ValidatorTags []string
+
 	// ExpandedStruct will cause the toplevel definitions of the schema not
 	// be referenced itself to a definition.
 	ExpandedStruct bool
@@ -141,9 +160,8 @@ type Reflector struct {
 
 	// Use package paths as well as type names, to avoid conflicts.
 	// Without this setting, if two packages contain a type with the same name,
-	// and both are present in a schema, they will conflict and overwrite in
-	// the definition map and produce bad output.  This is particularly
-	// noticeable when using DoNotReference.
+	// and both are present in a schema, their definitions are named after the
+	// shortest unique suffix of their package paths instead.
 	FullyQualifyTypeNames bool
 
 	// IgnoredTypes defines a slice of types that should be ignored in the schema,
@@ -156,8 +174,17 @@ type Reflector struct {
 	// TypeNamer allows customizing of type names
 	TypeNamer func(reflect.Type) string
 
+	// FieldNamer names the properties of exported fields whose tags do not
+	// name them, from their Go field name. Use SnakeCase, CamelCase,
+	// KebabCase or LowerCase to match the encoder in use. By default the field
+	// name is used as is.
+	FieldNamer func(string) string
+
 	// AdditionalFields allows adding structfields for a given type
 	AdditionalFields func(reflect.Type) []reflect.StructField
+
+	// names tracks the types named while reflecting a schema.
+	names *typeNames
 }
 
 // Reflect reflects to Schema from a value.
@@ -165,8 +192,29 @@ func (r *Reflector) Reflect(v interface{}) *Schema {
 	return r.ReflectFromType(reflect.TypeOf(v))
 }
 
-// ReflectFromType generates root schema
+// ReflectFromType generates root schema.
+//
+// Distinct types that would share a definition name, such as two types named
+// Config from different packages, are told apart by prefixing their names
+// with the shortest package path suffix that is unique, as in "api.Config"
+// and "db.Config".
 func (r *Reflector) ReflectFromType(t reflect.Type) *Schema {
+	s, _ := r.reflectFromTypeNamed(t)
+	return s
+}
+
+// reflectFromTypeNamed reflects t as ReflectFromType does, also returning the
+// Reflector whose typeName gives the names of its definitions.
+func (r *Reflector) reflectFromTypeNamed(t reflect.Type) (*Schema, *Reflector) {
+	var s *Schema
+	named, _ := r.reflectNamed(func(r *Reflector) error {
+		s = r.reflectFromType(t)
+		return nil
+	})
+	return s, named
+}
+
+func (r *Reflector) reflectFromType(t reflect.Type) *Schema {
 	definitions := Definitions{}
 	if r.ExpandedStruct {
 		st := &Type{
@@ -220,6 +268,7 @@ var protoEnumType = reflect.TypeOf((*protoEnum)(nil)).Elem()
 func (r *Reflector) reflectTypeToSchema(definitions Definitions, t reflect.Type) *Type {
 	// Already added to definitions?
 	if _, ok := definitions[r.typeName(t)]; ok && !r.DoNotReference {
+		r.names.add(r.typeName(t), t)
 		return &Type{Ref: "#/definitions/" + r.typeName(t)}
 	}
 
@@ -327,6 +376,55 @@ func (r *Reflector) reflectTypeToSchema(definitions Definitions, t reflect.Type)
 	panic("unsupported type " + t.String())
 }
 
+// unsupportedType returns the first type reachable from t that Reflect cannot
+// represent, such as a func or chan, or nil if there is none.
+func (r *Reflector) unsupportedType(t reflect.Type, seen map[reflect.Type]bool) reflect.Type {
+	if seen[t] {
+		return nil
+	}
+	seen[t] = true
+	if r.TypeMapper != nil && r.TypeMapper(t) != nil {
+		return nil
+	}
+	if t.Implements(customType) || t.Implements(protoEnumType) {
+		return nil
+	}
+	for _, ignored := range r.IgnoredTypes {
+		if reflect.TypeOf(ignored) == t {
+			return nil
+		}
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		if t == timeType || t == uriType {
+			return nil
+		}
+		fields := make([]reflect.StructField, 0, t.NumField())
+		for i := 0; i < t.NumField(); i++ {
+			fields = append(fields, t.Field(i))
+		}
+		if r.AdditionalFields != nil {
+			fields = append(fields, r.AdditionalFields(t)...)
+		}
+		for _, f := range fields {
+			if name, embed, _, _ := r.reflectFieldName(f); name == "" && !embed {
+				continue
+			}
+			if unsupported := r.unsupportedType(f.Type, seen); unsupported != nil {
+				return unsupported
+			}
+		}
+		return nil
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.Ptr:
+		return r.unsupportedType(t.Elem(), seen)
+	case reflect.Interface, reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return nil
+	}
+	return t
+}
+
 func (r *Reflector) reflectCustomType(definitions Definitions, t reflect.Type) *Type {
 	if t.Kind() == reflect.Ptr {
 		return r.reflectCustomType(definitions, t.Elem())
@@ -336,7 +434,7 @@ func (r *Reflector) reflectCustomType(definitions Definitions, t reflect.Type) *
 		v := reflect.New(t)
 		o := v.Interface().(customSchemaType)
 		st := o.JSONSchemaType()
-		definitions[r.typeName(t)] = st
+		r.define(definitions, t, st)
 		if r.DoNotReference {
 			return st
 		} else {
@@ -363,7 +461,7 @@ func (r *Reflector) reflectStruct(definitions Definitions, t reflect.Type) *Type
 				Properties:           orderedmap.New(),
 				AdditionalProperties: []byte("true"),
 			}
-			definitions[r.typeName(t)] = st
+			r.define(definitions, t, st)
 
 			if r.DoNotReference {
 				return st
@@ -384,7 +482,13 @@ func (r *Reflector) reflectStruct(definitions Definitions, t reflect.Type) *Type
 	if r.AllowAdditionalProperties {
 		st.AdditionalProperties = []byte("true")
 	}
-	definitions[r.typeName(t)] = st
+	if t.Name() == "" {
+		// Anonymous structs have no name to define them under, and
+		// cannot refer to themselves, so they are always inlined.
+		r.reflectStructFields(st, definitions, t)
+		return st
+	}
+	r.define(definitions, t, st)
 	r.reflectStructFields(st, definitions, t)
 
 	if r.DoNotReference {
@@ -424,6 +528,13 @@ func (r *Reflector) reflectStructFields(st *Type, definitions Definitions, t ref
 		}
 
 		property := r.reflectTypeToSchema(definitions, f.Type)
+		validateRequired, err := r.validateTagKeywords(property, f)
+		if err != nil {
+			panic(fmt.Errorf("jsonschema: %s.%s: %v", t, f.Name, err))
+		}
+		if validateRequired {
+			required = true
+		}
 		property.structKeywordsFromTags(f, st, name)
 		if getFieldDocString != nil {
 			property.Description = getFieldDocString(f.Name)
@@ -477,6 +588,39 @@ func (t *Type) structKeywordsFromTags(f reflect.StructField, parentType *Type, p
 	t.extraKeywords(extras)
 }
 
+// keywordsFromTags parses the keywords in the tags of f, as
+// reflectStructFields does, without reflecting the type of f.
+func keywordsFromTags(f reflect.StructField, propertyName string) *Type {
+	t := &Type{Type: tagType(f.Type)}
+	if t.Type == "array" {
+		t.Items = &Type{Type: tagType(f.Type.Elem())}
+	}
+	t.structKeywordsFromTags(f, &Type{}, propertyName)
+	return t
+}
+
+// tagType returns the JSON Schema type used to parse the tags of a field of
+// type t.
+func tagType(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() != reflect.Uint8 {
+			return "array"
+		}
+	}
+	return ""
+}
+
 // read struct tags for generic keyworks
 func (t *Type) genericKeywords(tags []string, parentType *Type, propertyName string) {
 	for _, tag := range tags {
@@ -681,19 +825,6 @@ func (t *Type) setExtra(key, val string) {
 	}
 }
 
-func requiredFromJSONTags(tags []string) bool {
-	if ignoredByJSONTags(tags) {
-		return false
-	}
-
-	for _, tag := range tags[1:] {
-		if tag == "omitempty" {
-			return false
-		}
-	}
-	return true
-}
-
 func requiredFromJSONSchemaTags(tags []string) bool {
 	if ignoredByJSONSchemaTags(tags) {
 		return false
@@ -718,17 +849,52 @@ func nullableFromJSONSchemaTags(tags []string) bool {
 	return false
 }
 
-func inlineYAMLTags(tags []string) bool {
-	for _, tag := range tags {
-		if tag == "inline" {
-			return true
+// fieldTag is a field's tag under one of the Reflector's TagKeys, such as
+// `json:"name,omitempty"`.
+type fieldTag struct {
+	name      string
+	ignored   bool
+	omitEmpty bool
+	inline    bool
+}
+
+// tagInlineOptions holds the option that embeds a field's struct, for tag keys
+// where it is not "inline". encoding/json has no such option, and toml uses
+// inline for inline tables instead.
+var tagInlineOptions = map[string]string{
+	"json":         "",
+	"mapstructure": "squash",
+	"koanf":        "squash",
+	"toml":         "",
+}
+
+func parseFieldTag(key, tag string) fieldTag {
+	list := strings.Split(tag, ",")
+	inlineOption, ok := tagInlineOptions[key]
+	if !ok {
+		inlineOption = "inline"
+	}
+	parsed := fieldTag{name: list[0], ignored: list[0] == "-"}
+	for _, option := range list[1:] {
+		switch option {
+		case "omitempty":
+			parsed.omitEmpty = true
+		case inlineOption:
+			parsed.inline = option != ""
 		}
 	}
-	return false
+	return parsed
 }
 
-func ignoredByJSONTags(tags []string) bool {
-	return tags[0] == "-"
+// tagKeys returns the struct tags that name fields, in order of precedence.
+func (r *Reflector) tagKeys() []string {
+	switch {
+	case len(r.TagKeys) > 0:
+		return r.TagKeys
+	case r.PreferYAMLSchema:
+		return []string{"yaml"}
+	}
+	return []string{"json", "yaml"}
 }
 
 func ignoredByJSONSchemaTags(tags []string) bool {
@@ -736,17 +902,21 @@ func ignoredByJSONSchemaTags(tags []string) bool {
 }
 
 func (r *Reflector) reflectFieldName(f reflect.StructField) (string, bool, bool, bool) {
-	jsonTags, exist := f.Tag.Lookup("json")
-	yamlTags, yamlExist := f.Tag.Lookup("yaml")
-	if !exist || r.PreferYAMLSchema {
-		jsonTags = yamlTags
-		exist = yamlExist
+	// The first tag present names the field; any of them may inline it.
+	tag, exist, inline := fieldTag{}, false, false
+	for _, key := range r.tagKeys() {
+		value, ok := f.Tag.Lookup(key)
+		if !ok {
+			continue
+		}
+		parsed := parseFieldTag(key, value)
+		if !exist {
+			tag, exist = parsed, true
+		}
+		inline = inline || parsed.inline
 	}
 
-	jsonTagsList := strings.Split(jsonTags, ",")
-	yamlTagsList := strings.Split(yamlTags, ",")
-
-	if ignoredByJSONTags(jsonTagsList) {
+	if tag.ignored {
 		return "", false, false, false
 	}
 
@@ -756,7 +926,7 @@ func (r *Reflector) reflectFieldName(f reflect.StructField) (string, bool, bool,
 	}
 
 	name := f.Name
-	required := requiredFromJSONTags(jsonTagsList)
+	required := !tag.omitEmpty
 
 	if r.RequiredFromJSONSchemaTags {
 		required = requiredFromJSONSchemaTags(jsonSchemaTags)
@@ -764,8 +934,10 @@ func (r *Reflector) reflectFieldName(f reflect.StructField) (string, bool, bool,
 
 	nullable := nullableFromJSONSchemaTags(jsonSchemaTags)
 
-	if jsonTagsList[0] != "" {
-		name = jsonTagsList[0]
+	if tag.name != "" {
+		name = tag.name
+	} else if r.FieldNamer != nil && !f.Anonymous {
+		name = r.FieldNamer(name)
 	}
 
 	// field not anonymous and not export has no export name
@@ -785,7 +957,7 @@ func (r *Reflector) reflectFieldName(f reflect.StructField) (string, bool, bool,
 		}
 	}
 
-	if yamlExist && inlineYAMLTags(yamlTagsList) {
+	if inline {
 		name = ""
 		embed = true
 	}
@@ -816,8 +988,8 @@ func (s *Schema) MarshalJSON() ([]byte, error) {
 }
 
 func (t *Type) MarshalJSON() ([]byte, error) {
-	type Type_ Type
-	b, err := json.Marshal((*Type_)(t))
+	type plainType Type
+	b, err := json.Marshal((*plainType)(t))
 	if err != nil {
 		return nil, err
 	}
@@ -836,14 +1008,221 @@ func (t *Type) MarshalJSON() ([]byte, error) {
 	}
 }
 
+// UnmarshalJSON parses a schema, keeping its properties in document order as
+// *Type values and its unknown keywords in Extras.
+func (s *Schema) UnmarshalJSON(b []byte) error {
+	t := &Type{}
+	if err := json.Unmarshal(b, t); err != nil {
+		return err
+	}
+	s.Type = t
+	s.Definitions = t.Definitions
+	t.Definitions = nil
+	return nil
+}
+
+// UnmarshalJSON parses a schema, keeping its properties in document order as
+// *Type values and its unknown keywords in Extras.
+func (t *Type) UnmarshalJSON(b []byte) error {
+	// Boolean schemas accept everything or nothing.
+	switch string(bytes.TrimSpace(b)) {
+	case "true":
+		*t = Type{}
+		return nil
+	case "false":
+		*t = Type{Not: &Type{}}
+		return nil
+	}
+
+	var keywords map[string]json.RawMessage
+	if err := json.Unmarshal(b, &keywords); err != nil {
+		return err
+	}
+	// Keywords the fields cannot hold, such as a fractional maximum or
+	// an array of items, are left out here and kept in Extras below.
+	representable := make(map[string]json.RawMessage, len(keywords))
+	for key, raw := range keywords {
+		if representableKeyword(key, raw) {
+			representable[key] = raw
+		}
+	}
+	b, err := json.Marshal(representable)
+	if err != nil {
+		return err
+	}
+
+	type plainType Type
+	aux := struct {
+		*plainType
+		Type         json.RawMessage            `json:"type,omitempty"`
+		Properties   json.RawMessage            `json:"properties,omitempty"`
+		Dependencies map[string]json.RawMessage `json:"dependencies,omitempty"`
+	}{plainType: (*plainType)(t)}
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+	t.Properties = nil
+	if len(aux.Properties) > 0 && string(aux.Properties) != "null" {
+		properties, err := unmarshalProperties(aux.Properties)
+		if err != nil {
+			return err
+		}
+		t.Properties = properties
+	}
+
+	// A list of types is equivalent to an anyOf of each type.
+	t.Type = ""
+	typeList := false
+	if len(aux.Type) > 0 && aux.Type[0] == '[' {
+		var types []string
+		if err := json.Unmarshal(aux.Type, &types); err != nil {
+			return err
+		}
+		var alternatives []*Type
+		for _, ty := range types {
+			alternatives = append(alternatives, &Type{Type: ty})
+		}
+		if len(t.AnyOf) == 0 {
+			t.AnyOf = alternatives
+		} else {
+			t.AllOf = append(t.AllOf, &Type{AnyOf: alternatives})
+		}
+		typeList = true
+	} else if len(aux.Type) > 0 {
+		if err := json.Unmarshal(aux.Type, &t.Type); err != nil {
+			return err
+		}
+	}
+
+	// A property dependency is equivalent to a schema requiring the
+	// properties.
+	t.Dependencies = nil
+	for key, raw := range aux.Dependencies {
+		dependency := &Type{}
+		if len(raw) > 0 && raw[0] == '[' {
+			if err := json.Unmarshal(raw, &dependency.Required); err != nil {
+				return err
+			}
+		} else if err := json.Unmarshal(raw, dependency); err != nil {
+			return err
+		}
+		if t.Dependencies == nil {
+			t.Dependencies = map[string]*Type{}
+		}
+		t.Dependencies[key] = dependency
+	}
+
+	t.Extras = nil
+	v := reflect.ValueOf(t).Elem()
+	for key, raw := range keywords {
+		// Keywords whose value would be omitted when marshalling, such as a
+		// minimum of 0, are kept in Extras so that they are not lost.
+		if i, ok := typeKeywords[key]; ok && (!isEmptyValue(v.Field(i)) || key == "type" && typeList) {
+			continue
+		}
+		var v interface{}
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return err
+		}
+		if t.Extras == nil {
+			t.Extras = map[string]interface{}{}
+		}
+		t.Extras[key] = v
+	}
+	return nil
+}
+
+// representableKeyword reports whether the value raw of key fits the field of
+// Type for it: integer limits, an items schema rather than an array, and an
+// additionalItems schema rather than a boolean.
+func representableKeyword(key string, raw json.RawMessage) bool {
+	i, ok := typeKeywords[key]
+	if !ok {
+		return true
+	}
+	raw = bytes.TrimSpace(raw)
+	switch {
+	case reflect.TypeOf(Type{}).Field(i).Type.Kind() == reflect.Int:
+		var n int
+		return json.Unmarshal(raw, &n) == nil
+	case key == "items":
+		return len(raw) == 0 || raw[0] != '['
+	case key == "additionalItems":
+		return string(raw) != "true" && string(raw) != "false"
+	}
+	return true
+}
+
+// typeKeywords maps the keywords that have a field in Type to its index.
+var typeKeywords = func() map[string]int {
+	keywords := map[string]int{}
+	t := reflect.TypeOf(Type{})
+	for i := 0; i < t.NumField(); i++ {
+		if name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]; name != "" && name != "-" {
+			keywords[name] = i
+		}
+	}
+	return keywords
+}()
+
+// isEmptyValue reports whether v is omitted by encoding/json's omitempty.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
+
+func unmarshalProperties(b []byte) (*orderedmap.OrderedMap, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	properties := orderedmap.New()
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := token.(string)
+		property := &Type{}
+		if err := dec.Decode(property); err != nil {
+			return nil, err
+		}
+		properties.Set(key, property)
+	}
+	return properties, nil
+}
+
+// define adds st to definitions as the schema of t.
+func (r *Reflector) define(definitions Definitions, t reflect.Type, st *Type) {
+	name := r.typeName(t)
+	r.names.add(name, t)
+	definitions[name] = st
+}
+
 func (r *Reflector) typeName(t reflect.Type) string {
+	if r.names != nil {
+		if name, ok := r.names.renamed[t]; ok {
+			return name
+		}
+	}
 	if r.TypeNamer != nil {
 		if name := r.TypeNamer(t); name != "" {
 			return name
 		}
 	}
-	if r.FullyQualifyTypeNames {
-		return t.PkgPath() + "." + t.Name()
+	if r.FullyQualifyTypeNames && t.Name() != "" {
+		return t.PkgPath() + "." + genericTypeName(t.Name())
 	}
-	return t.Name()
+	return genericTypeName(t.Name())
 }
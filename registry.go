@@ -0,0 +1,261 @@
+package jsonschema
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// A Registry collects the schemas of several Go types, each with a stable id
+// under a base URL, and serves them over HTTP.
+//
+// References from one registered schema to the type of another point to the
+// other schema's URL rather than to a copy in its definitions.
+type Registry struct {
+	baseURL   string
+	reflector *Reflector
+
+	mu      sync.RWMutex
+	types   map[string]reflect.Type
+	byType  map[reflect.Type]string
+	ordered []string
+	docs    map[string]*registryDoc
+}
+
+type registryDoc struct {
+	body []byte
+	etag string
+}
+
+// RegistryEntry describes a schema in the index served by a Registry.
+type RegistryEntry struct {
+	Name string `json:"name"`
+	ID   string `json:"$id"`
+}
+
+var (
+	registryNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+	// legacyIDPattern matches the $schema of drafts that name the id keyword
+	// id rather than $id.
+	legacyIDPattern = regexp.MustCompile(`/draft-0[0-4]/`)
+)
+
+// NewRegistry creates a Registry whose schemas have ids of the form
+// baseURL + name + ".json", reflected with r. A nil r uses an empty
+// Reflector.
+func NewRegistry(baseURL string, r *Reflector) *Registry {
+	if r == nil {
+		r = &Reflector{}
+	}
+	return &Registry{
+		baseURL:   baseURL,
+		reflector: r,
+		types:     map[string]reflect.Type{},
+		byType:    map[reflect.Type]string{},
+	}
+}
+
+// Register adds the types of values, named as the Reflector names their
+// definitions.
+func (g *Registry) Register(values ...interface{}) error {
+	for _, v := range values {
+		t := registryType(v)
+		if err := g.RegisterName(g.reflector.typeName(t), v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RegisterName adds the type of v under name.
+func (g *Registry) RegisterName(name string, v interface{}) error {
+	t := registryType(v)
+	if !registryNamePattern.MatchString(name) || name == "index" {
+		return fmt.Errorf("jsonschema: cannot register %s as %q: invalid schema name", t, name)
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if existing, ok := g.types[name]; ok {
+		if existing == t {
+			return nil
+		}
+		return fmt.Errorf("jsonschema: cannot register %s as %q: already registered for %s", t, name, existing)
+	}
+	if existing, ok := g.byType[t]; ok {
+		return fmt.Errorf("jsonschema: cannot register %s as %q: already registered as %q", t, name, existing)
+	}
+	g.types[name] = t
+	g.byType[t] = name
+	g.ordered = append(g.ordered, name)
+	sort.Strings(g.ordered)
+	g.docs = nil
+	return nil
+}
+
+func registryType(v interface{}) reflect.Type {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// URL returns the id of the schema registered under name.
+func (g *Registry) URL(name string) string {
+	return g.baseURL + name + ".json"
+}
+
+// Names returns the names of the registered schemas, sorted.
+func (g *Registry) Names() []string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return append([]string{}, g.ordered...)
+}
+
+// Schema returns the schema registered under name, with references to other
+// registered types resolved to their URLs.
+func (g *Registry) Schema(name string) (*Schema, bool) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	t, ok := g.types[name]
+	if !ok {
+		return nil, false
+	}
+	return g.schema(name, t), true
+}
+
+// Load implements Loader, returning the schema whose URL is uri.
+func (g *Registry) Load(uri string) (*Schema, error) {
+	uri = trimFragment(uri)
+	name := strings.TrimSuffix(strings.TrimPrefix(uri, g.baseURL), ".json")
+	if g.URL(name) == uri {
+		if s, ok := g.Schema(name); ok {
+			return s, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrSchemaNotFound, uri)
+}
+
+func (g *Registry) schema(name string, t reflect.Type) *Schema {
+	s, named := g.reflector.reflectFromTypeNamed(t)
+	setSchemaID(s.Type, g.URL(name))
+	own := named.typeName(t)
+
+	// Map the definition names of registered types to their schema names.
+	registered := map[string]string{}
+	for other, name := range g.byType {
+		registered[named.typeName(other)] = name
+	}
+	s.walk(func(t *Type) {
+		def, ok := definitionName(t.Ref)
+		if !ok || def == own {
+			return
+		}
+		if other, ok := registered[def]; ok {
+			t.Ref = g.URL(other)
+		}
+	})
+	reachable := s.reachableDefinitions()
+	for def := range s.Definitions {
+		if !reachable[def] && def != own {
+			delete(s.Definitions, def)
+		}
+	}
+	return s
+}
+
+// ServeHTTP serves each registered schema at {name}.json, relative to the
+// path the Registry is mounted at, and an index of all schemas at the mount
+// point itself or index.json. The Registry is mounted at the path of its base
+// URL, or at the root when used behind http.StripPrefix. Responses carry an
+// ETag and honour If-None-Match.
+func (g *Registry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	file := strings.TrimPrefix(r.URL.Path, "/")
+	if base, err := url.Parse(g.baseURL); err == nil && base.Path != "" && strings.HasPrefix(r.URL.Path, base.Path) {
+		file = strings.TrimPrefix(r.URL.Path, base.Path)
+	}
+	if file == "" {
+		file = "index.json"
+	}
+	if strings.Contains(file, "/") || !strings.HasSuffix(file, ".json") {
+		http.NotFound(w, r)
+		return
+	}
+	doc, err := g.document(strings.TrimSuffix(file, ".json"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if doc == nil {
+		http.NotFound(w, r)
+		return
+	}
+	contentType := "application/schema+json"
+	if file == "index.json" {
+		contentType = "application/json"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("ETag", doc.etag)
+	http.ServeContent(w, r, file, time.Time{}, bytes.NewReader(doc.body))
+}
+
+// document returns the rendered schema or index named name, or nil if there
+// is none.
+func (g *Registry) document(name string) (*registryDoc, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if doc, ok := g.docs[name]; ok {
+		return doc, nil
+	}
+	var v interface{}
+	if name == "index" {
+		index := []*RegistryEntry{}
+		for _, name := range g.ordered {
+			index = append(index, &RegistryEntry{Name: name, ID: g.URL(name)})
+		}
+		v = index
+	} else if t, ok := g.types[name]; ok {
+		v = g.schema(name, t)
+	} else {
+		return nil, nil
+	}
+	body, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(body)
+	doc := &registryDoc{body: body, etag: `"` + hex.EncodeToString(sum[:16]) + `"`}
+	if g.docs == nil {
+		g.docs = map[string]*registryDoc{}
+	}
+	g.docs[name] = doc
+	return doc, nil
+}
+
+// setSchemaID identifies t by id, with the id keyword if t declares draft-04
+// or earlier and with $id, which replaced it, otherwise.
+func setSchemaID(t *Type, id string) {
+	if !legacyIDPattern.MatchString(t.Version) {
+		t.ID = id
+		return
+	}
+	if t.Extras == nil {
+		t.Extras = map[string]interface{}{}
+	}
+	t.Extras["id"] = id
+}
@@ -0,0 +1,144 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type RegistryAddress struct {
+	City string `json:"city"`
+}
+
+type RegistryCustomer struct {
+	Name    string          `json:"name"`
+	Address RegistryAddress `json:"address"`
+}
+
+type RegistryItem struct {
+	SKU string `json:"sku"`
+}
+
+type RegistryOrder struct {
+	Customer *RegistryCustomer `json:"customer"`
+	Items    []RegistryItem    `json:"items"`
+}
+
+func TestRegistry(t *testing.T) {
+	g := NewRegistry("https://example.com/schemas/", nil)
+	require.NoError(t, g.Register(&RegistryOrder{}, RegistryCustomer{}))
+	require.NoError(t, g.Register(&RegistryOrder{}), "registering twice is a no-op")
+	require.Equal(t, []string{"RegistryCustomer", "RegistryOrder"}, g.Names())
+
+	require.EqualError(t, g.RegisterName("Order", &RegistryOrder{}),
+		`jsonschema: cannot register jsonschema.RegistryOrder as "Order": already registered as "RegistryOrder"`)
+	require.EqualError(t, g.RegisterName("RegistryOrder", &RegistryItem{}),
+		`jsonschema: cannot register jsonschema.RegistryItem as "RegistryOrder": already registered for jsonschema.RegistryOrder`)
+	require.Error(t, g.RegisterName("a/b", &RegistryItem{}))
+	require.Error(t, g.RegisterName("index", &RegistryItem{}))
+
+	s, ok := g.Schema("RegistryOrder")
+	require.True(t, ok)
+	b, err := json.Marshal(s)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"$schema": "http://json-schema.org/draft-04/schema#",
+		"id": "https://example.com/schemas/RegistryOrder.json",
+		"$ref": "#/definitions/RegistryOrder",
+		"definitions": {
+			"RegistryOrder": {
+				"type": "object",
+				"required": ["customer", "items"],
+				"properties": {
+					"customer": {"$schema": "http://json-schema.org/draft-04/schema#", "$ref": "https://example.com/schemas/RegistryCustomer.json"},
+					"items": {"type": "array", "items": {"$schema": "http://json-schema.org/draft-04/schema#", "$ref": "#/definitions/RegistryItem"}}
+				},
+				"additionalProperties": false
+			},
+			"RegistryItem": {
+				"type": "object",
+				"required": ["sku"],
+				"properties": {"sku": {"type": "string"}},
+				"additionalProperties": false
+			}
+		}
+	}`, string(b))
+
+	_, ok = g.Schema("RegistryItem")
+	require.False(t, ok)
+}
+
+func TestRegistryServeHTTP(t *testing.T) {
+	g := NewRegistry("https://example.com/schemas/", nil)
+	require.NoError(t, g.Register(&RegistryOrder{}, &RegistryCustomer{}))
+	mux := http.NewServeMux()
+	mux.Handle("/schemas/", g)
+
+	get := func(method, path, etag string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("GET", "/schemas/", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `[
+		{"name": "RegistryCustomer", "$id": "https://example.com/schemas/RegistryCustomer.json"},
+		{"name": "RegistryOrder", "$id": "https://example.com/schemas/RegistryOrder.json"}
+	]`, w.Body.String())
+
+	w = get("GET", "/schemas/RegistryCustomer.json", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "application/schema+json", w.Header().Get("Content-Type"))
+	require.Contains(t, w.Body.String(), `"id": "https://example.com/schemas/RegistryCustomer.json"`)
+	etag := w.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	require.Equal(t, http.StatusNotModified, get("GET", "/schemas/RegistryCustomer.json", etag).Code)
+	require.Equal(t, etag, get("HEAD", "/schemas/RegistryCustomer.json", "").Header().Get("ETag"))
+	require.Equal(t, http.StatusNotFound, get("GET", "/schemas/RegistryItem.json", "").Code)
+	require.Equal(t, http.StatusNotFound, get("GET", "/schemas/RegistryOrder", "").Code)
+	require.Equal(t, http.StatusNotFound, get("GET", "/schemas/a/b/RegistryOrder.json", "").Code)
+	require.Equal(t, http.StatusMethodNotAllowed, get("POST", "/schemas/RegistryOrder.json", "").Code)
+
+	stripped := httptest.NewRecorder()
+	http.StripPrefix("/api", g).ServeHTTP(stripped, httptest.NewRequest("GET", "/api/RegistryCustomer.json", nil))
+	require.Equal(t, http.StatusOK, stripped.Code)
+
+	// Registering another type changes the documents that refer to it.
+	before := get("GET", "/schemas/RegistryOrder.json", "").Header().Get("ETag")
+	require.NoError(t, g.Register(&RegistryItem{}))
+	w = get("GET", "/schemas/RegistryOrder.json", before)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), `"$ref": "https://example.com/schemas/RegistryItem.json"`)
+}
+
+func TestRegistrySchemaIDKeyword(t *testing.T) {
+	g := NewRegistry("https://example.com/schemas/", nil)
+	require.NoError(t, g.Register(&RegistryItem{}))
+	s, _ := g.Schema("RegistryItem")
+	require.Equal(t, "", s.ID)
+	require.Equal(t, "https://example.com/schemas/RegistryItem.json", s.Extras["id"])
+
+	defer func(version string) { Version = version }(Version)
+	Version = "http://json-schema.org/draft-07/schema#"
+	g = NewRegistry("https://example.com/schemas/", nil)
+	require.NoError(t, g.Register(&RegistryItem{}))
+	s, _ = g.Schema("RegistryItem")
+	require.Equal(t, "https://example.com/schemas/RegistryItem.json", s.ID)
+	require.Nil(t, s.Extras["id"])
+
+	// The keyword follows the schema's own draft, not the current Version.
+	Version = "http://json-schema.org/draft-04/schema#"
+	item := &Type{Version: "https://json-schema.org/draft/2020-12/schema"}
+	setSchemaID(item, "https://example.com/item.json")
+	require.Equal(t, "https://example.com/item.json", item.ID)
+	require.Nil(t, item.Extras)
+}
@@ -0,0 +1,95 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// SplitIndex is the name of the index file produced by SplitFiles.
+const SplitIndex = "index.json"
+
+var splitInvalidFileName = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// SplitFiles splits s into one schema file per definition, named after the
+// definition, with references between definitions rewritten to relative file
+// references such as "./User.json".
+//
+// The returned map also holds SplitIndex, a schema for the root type whose
+// definitions refer to every file. Files are keyed by file name and hold
+// indented JSON.
+func SplitFiles(s *Schema) (map[string][]byte, error) {
+	files := map[string]string{}
+	names := map[string]string{}
+	for _, name := range sortedKeys(s.Definitions) {
+		file := splitInvalidFileName.ReplaceAllString(name, "_") + ".json"
+		if file == SplitIndex {
+			return nil, fmt.Errorf("jsonschema: definition %q would be written to %s, which holds the index", name, file)
+		}
+		if other, ok := names[file]; ok {
+			return nil, fmt.Errorf("jsonschema: definitions %q and %q would both be written to %s", other, name, file)
+		}
+		files[name] = file
+		names[file] = name
+	}
+	rewrite := func(t *Type) *Type {
+		t = t.clone()
+		t.walk(func(t *Type) {
+			if name, ok := definitionName(t.Ref); ok {
+				if file, ok := files[name]; ok {
+					t.Ref = "./" + file
+				}
+			}
+		})
+		return t
+	}
+
+	out := map[string][]byte{}
+	for name, file := range files {
+		t := rewrite(s.Definitions[name])
+		t.Version = Version
+		b, err := json.MarshalIndent(t, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		out[file] = b
+	}
+
+	index := &Schema{Type: &Type{}}
+	if s.Type != nil {
+		index.Type = rewrite(s.Type)
+	}
+	index.Version = Version
+	if len(files) > 0 {
+		index.Definitions = Definitions{}
+		for name, file := range files {
+			index.Definitions[name] = &Type{Ref: "./" + file}
+		}
+	}
+	b, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	out[SplitIndex] = b
+	return out, nil
+}
+
+// WriteSplit writes the files of SplitFiles into dir, creating it if needed.
+func WriteSplit(dir string, s *Schema) error {
+	files, err := SplitFiles(s)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for file, b := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, file), append(b, '\n'), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package jsonschema
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type SplitUser struct {
+	Name    string        `json:"name"`
+	Friends []*SplitUser  `json:"friends,omitempty"`
+	Address *SplitAddress `json:"address,omitempty"`
+}
+
+type SplitAddress struct {
+	City string `json:"city"`
+}
+
+func TestWriteSplit(t *testing.T) {
+	dir, err := ioutil.TempDir("", "jsonschema-split")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	require.NoError(t, WriteSplit(dir, Reflect(&SplitUser{})))
+
+	written, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	require.NoError(t, err)
+	expected, err := filepath.Glob("fixtures/split/*.json")
+	require.NoError(t, err)
+	require.Equal(t, baseNames(expected), baseNames(written))
+	for _, file := range expected {
+		want, err := ioutil.ReadFile(file)
+		require.NoError(t, err)
+		got, err := ioutil.ReadFile(filepath.Join(dir, filepath.Base(file)))
+		require.NoError(t, err)
+		require.JSONEq(t, string(want), string(got), file)
+	}
+}
+
+func TestSplitFilesCollision(t *testing.T) {
+	s := &Schema{Type: &Type{}, Definitions: Definitions{"a/b": {}, "a.b": {}, "a_b": {}}}
+	_, err := SplitFiles(s)
+	require.EqualError(t, err, `jsonschema: definitions "a/b" and "a_b" would both be written to a_b.json`)
+
+	s = &Schema{Type: &Type{}, Definitions: Definitions{"index": {}}}
+	_, err = SplitFiles(s)
+	require.EqualError(t, err, `jsonschema: definition "index" would be written to index.json, which holds the index`)
+}
+
+func baseNames(paths []string) []string {
+	var out []string
+	for _, p := range paths {
+		out = append(out, filepath.Base(p))
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,87 @@
+package jsonschema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CreateTableSQL converts s, which must describe an object, into a
+// PostgreSQL CREATE TABLE statement with one column per property.
+//
+// Required properties are NOT NULL unless they are nullable, string enums
+// become CHECK constraints, and nested objects, arrays and maps are stored in
+// JSONB columns.
+func CreateTableSQL(s *Schema, table string) (string, error) {
+	root := s.Definitions.resolve(s.Type)
+	if root == nil || root.Type != "object" || root.Properties == nil {
+		return "", fmt.Errorf("root schema must be an object with properties")
+	}
+	var columns []string
+	for _, key := range root.Properties.Keys() {
+		v, _ := root.Properties.Get(key)
+		p, ok := v.(*Type)
+		if !ok {
+			return "", fmt.Errorf("%s: property is not a schema", key)
+		}
+		notNull := contains(root.Required, key)
+		if sub, ok := nullableOneOf(p); ok {
+			p = sub
+			notNull = false
+		}
+		p = s.Definitions.resolve(p)
+		if p == nil {
+			return "", fmt.Errorf("%s: unresolvable $ref", key)
+		}
+		column := sqlIdentifier(key) + " " + sqlColumnType(p)
+		if notNull {
+			column += " NOT NULL"
+		}
+		if check := sqlEnumCheck(key, p); check != "" {
+			column += " " + check
+		}
+		columns = append(columns, column)
+	}
+	return fmt.Sprintf("CREATE TABLE %s (\n  %s\n);\n", sqlIdentifier(table), strings.Join(columns, ",\n  ")), nil
+}
+
+func sqlColumnType(t *Type) string {
+	switch t.Type {
+	case "string":
+		switch {
+		case t.Format == "date-time":
+			return "TIMESTAMPTZ"
+		case t.Format == "date":
+			return "DATE"
+		case t.Media != nil && t.Media.BinaryEncoding == "base64":
+			return "BYTEA"
+		}
+		return "TEXT"
+	case "integer":
+		return "BIGINT"
+	case "number":
+		return "DOUBLE PRECISION"
+	case "boolean":
+		return "BOOLEAN"
+	}
+	return "JSONB"
+}
+
+func sqlEnumCheck(column string, t *Type) string {
+	if t.Type != "string" || len(t.Enum) == 0 {
+		return ""
+	}
+	var values []string
+	for _, v := range t.Enum {
+		s, ok := v.(string)
+		if !ok {
+			return ""
+		}
+		values = append(values, "'"+strings.ReplaceAll(s, "'", "''")+"'")
+	}
+	return fmt.Sprintf("CHECK (%s IN (%s))", sqlIdentifier(column), strings.Join(values, ", "))
+}
+
+// sqlIdentifier quotes name, so that reserved words and mixed case survive.
+func sqlIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
@@ -0,0 +1,19 @@
+package jsonschema
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateTableSQL(t *testing.T) {
+	actual, err := CreateTableSQL(Reflect(&TableRow{}), "rows")
+	require.NoError(t, err)
+	expected, err := ioutil.ReadFile("fixtures/create_table.sql")
+	require.NoError(t, err)
+	require.Equal(t, string(expected), actual)
+
+	_, err = CreateTableSQL(Reflect(""), "rows")
+	require.Error(t, err)
+}
@@ -0,0 +1,131 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/iancoleman/orderedmap"
+)
+
+// StrictToolFormats are the string formats accepted in strict tool schemas.
+var StrictToolFormats = []string{
+	"date-time", "date", "time", "duration", "email", "hostname", "ipv4", "ipv6", "uuid",
+}
+
+// ReflectStrictTool reflects v into a strict tool schema.
+// See StrictToolSchema for details.
+func (r *Reflector) ReflectStrictTool(v interface{}) (*Type, error) {
+	return StrictToolSchema(r.Reflect(v))
+}
+
+// StrictToolSchema converts s into the restricted dialect required for LLM
+// tool/function-calling definitions:
+//
+//   - all $refs are inlined, so recursive types are rejected;
+//   - every object has additionalProperties set to false;
+//   - every property is required, optional properties become nullable instead;
+//   - oneOf is rewritten as anyOf;
+//   - only the formats in StrictToolFormats are allowed.
+//
+// Maps, free-form values (interface{}, json.RawMessage), oneof_required groups
+// and the allOf, not, dependencies and additionalItems keywords cannot be
+// expressed and are reported in a *ConversionError.
+func StrictToolSchema(s *Schema) (*Type, error) {
+	c := &strictTool{inliner: newInliner(s.Definitions)}
+	out := c.convert(s.Type, "")
+	if out.Type != "object" {
+		c.problem("", "root schema must be an object")
+	}
+	if err := c.asError("a strict tool schema"); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+type strictTool struct {
+	inliner
+}
+
+func (c *strictTool) convert(t *Type, path string) *Type {
+	if t.Ref != "" {
+		return c.inline(t, path, c.convert)
+	}
+
+	out := *t
+	out.Version = ""
+	out.Definitions = nil
+	out.OneOf = nil
+	out.AnyOf = nil
+
+	if t.Format != "" && !contains(StrictToolFormats, t.Format) {
+		c.problem(path, "unsupported format "+t.Format)
+	}
+	if len(t.PatternProperties) > 0 {
+		c.problem(path, "maps (patternProperties) are not supported")
+	}
+	if len(t.Dependencies) > 0 {
+		c.problem(path, "dependencies are not supported")
+	}
+	if len(t.AllOf) > 0 {
+		c.problem(path, "allOf is not supported")
+	}
+	if t.Not != nil {
+		c.problem(path, "not is not supported")
+	}
+	if t.AdditionalItems != nil {
+		c.problem(path, "additionalItems is not supported")
+	}
+	if t.Type == "" && len(t.OneOf) == 0 && len(t.AnyOf) == 0 && len(t.Enum) == 0 {
+		c.problem(path, "free-form values without a type are not supported")
+	}
+
+	if t.Items != nil {
+		out.Items = c.convert(t.Items, path+"/items")
+	}
+	for i, sub := range t.AnyOf {
+		out.AnyOf = append(out.AnyOf, c.convert(sub, path+"/anyOf/"+strconv.Itoa(i)))
+	}
+	for i, sub := range t.OneOf {
+		if sub.Type == "" && len(sub.Required) > 0 {
+			c.problem(path, "oneof_required groups are not supported")
+			continue
+		}
+		out.AnyOf = append(out.AnyOf, c.convert(sub, path+"/oneOf/"+strconv.Itoa(i)))
+	}
+
+	if t.Type == "object" {
+		out.Properties = orderedmap.New()
+		out.Required = nil
+		out.AdditionalProperties = json.RawMessage("false")
+		if t.Properties != nil {
+			for _, key := range t.Properties.Keys() {
+				v, _ := t.Properties.Get(key)
+				p, ok := v.(*Type)
+				if !ok {
+					c.problem(path+"/properties/"+pointerEscape(key), "property is not a schema")
+					continue
+				}
+				p = c.convert(p, path+"/properties/"+pointerEscape(key))
+				if !contains(t.Required, key) {
+					p = strictNullable(p)
+				}
+				out.Properties.Set(key, p)
+				out.Required = append(out.Required, key)
+			}
+		}
+	}
+	return &out
+}
+
+// strictNullable makes t accept null as well, unless it already does.
+func strictNullable(t *Type) *Type {
+	if t.Type == "null" {
+		return t
+	}
+	for _, sub := range t.AnyOf {
+		if sub.Type == "null" {
+			return t
+		}
+	}
+	return &Type{AnyOf: []*Type{t, {Type: "null"}}}
+}
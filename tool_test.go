@@ -0,0 +1,55 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type WeatherLocation struct {
+	City    string `json:"city" jsonschema:"description=name of the city"`
+	Country string `json:"country,omitempty" jsonschema:"minLength=2,maxLength=2"`
+}
+
+type GetWeatherArgs struct {
+	Location WeatherLocation `json:"location"`
+	Units    string          `json:"units,omitempty" jsonschema:"enum=celsius,enum=fahrenheit"`
+	Days     []int           `json:"days"`
+	Note     string          `json:"note" jsonschema:"nullable"`
+	When     time.Time       `json:"when,omitempty"`
+}
+
+type UnsupportedToolArgs struct {
+	Extra   map[string]string    `json:"extra"`
+	Website url.URL              `json:"website"`
+	Any     interface{}          `json:"any"`
+	Next    *UnsupportedToolArgs `json:"next,omitempty"`
+}
+
+func TestStrictToolSchema(t *testing.T) {
+	actual, err := (&Reflector{}).ReflectStrictTool(&GetWeatherArgs{})
+	require.NoError(t, err)
+	actualJSON, err := json.MarshalIndent(actual, "", "  ")
+	require.NoError(t, err)
+	expectedJSON, err := ioutil.ReadFile("fixtures/strict_tool.json")
+	require.NoError(t, err)
+	require.JSONEq(t, string(expectedJSON), string(actualJSON))
+}
+
+func TestStrictToolSchemaUnsupported(t *testing.T) {
+	_, err := (&Reflector{}).ReflectStrictTool(&UnsupportedToolArgs{})
+	require.Error(t, err)
+	require.Equal(t, []string{
+		"/properties/extra: maps (patternProperties) are not supported",
+		"/properties/website: unsupported format uri",
+		"/properties/any: free-form values without a type are not supported",
+		"/properties/next: recursive reference to UnsupportedToolArgs cannot be inlined",
+	}, err.(*ConversionError).Problems)
+
+	_, err = StrictToolSchema(Reflect([]string{}))
+	require.EqualError(t, err, "jsonschema: schema cannot be expressed as a strict tool schema:\n  /: root schema must be an object")
+}
@@ -0,0 +1,215 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// WriteTypeScript renders s as TypeScript declarations.
+//
+// Every definition with properties becomes an exported interface, other
+// definitions become exported type aliases. Properties missing from Required
+// are optional, enums become unions of literals, oneOf and anyOf become union
+// types, allOf becomes an intersection and patternProperties become
+// Record<string, T>.
+func WriteTypeScript(w io.Writer, s *Schema) error {
+	b := &strings.Builder{}
+	first := true
+	declare := func(name string, t *Type) {
+		if !first {
+			b.WriteString("\n")
+		}
+		first = false
+		tsComment(b, t, "")
+		if t.Type == "object" && t.Properties != nil {
+			fmt.Fprintf(b, "export interface %s %s\n", tsIdentifier(name), tsObject(t, ""))
+		} else {
+			fmt.Fprintf(b, "export type %s = %s;\n", tsIdentifier(name), tsType(t, ""))
+		}
+	}
+	if _, ok := definitionName(s.Ref); !ok && s.Type != nil {
+		name := s.Title
+		if name == "" {
+			name = "Root"
+		}
+		declare(name, s.Type)
+	}
+	for _, name := range sortedKeys(s.Definitions) {
+		declare(name, s.Definitions[name])
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func tsType(t *Type, indent string) string {
+	if name, ok := definitionName(t.Ref); ok {
+		return tsIdentifier(name)
+	}
+	if t.Ref != "" {
+		return "unknown"
+	}
+	if len(t.Enum) > 0 {
+		var literals []string
+		for _, v := range t.Enum {
+			b, err := json.Marshal(v)
+			if err != nil {
+				continue
+			}
+			literals = append(literals, string(b))
+		}
+		return strings.Join(literals, " | ")
+	}
+	if t.Type == "" {
+		if alternatives := tsAlternatives(t); len(alternatives) > 0 {
+			return tsUnion(alternatives, indent)
+		}
+		if len(t.AllOf) > 0 {
+			var parts []string
+			for _, sub := range t.AllOf {
+				parts = append(parts, tsParen(tsType(sub, indent)))
+			}
+			return strings.Join(parts, " & ")
+		}
+	}
+	switch t.Type {
+	case "string":
+		return "string"
+	case "integer", "number":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "null":
+		return "null"
+	case "array":
+		if t.Items == nil {
+			return "unknown[]"
+		}
+		return tsParen(tsType(t.Items, indent)) + "[]"
+	case "object":
+		if t.Properties != nil && len(t.Properties.Keys()) > 0 {
+			return tsObject(t, indent)
+		}
+		if len(t.PatternProperties) > 0 {
+			var values []*Type
+			for _, key := range sortedKeys(t.PatternProperties) {
+				values = append(values, t.PatternProperties[key])
+			}
+			return "Record<string, " + tsUnion(values, indent) + ">"
+		}
+		return "Record<string, unknown>"
+	}
+	return "unknown"
+}
+
+// tsAlternatives returns the oneOf or anyOf alternatives that describe a type,
+// skipping oneof_required groups.
+func tsAlternatives(t *Type) []*Type {
+	var out []*Type
+	for _, sub := range append(append([]*Type{}, t.OneOf...), t.AnyOf...) {
+		if sub.Type == "" && sub.Ref == "" && len(sub.Required) > 0 {
+			continue
+		}
+		out = append(out, sub)
+	}
+	return out
+}
+
+func tsUnion(types []*Type, indent string) string {
+	var parts []string
+	seen := map[string]bool{}
+	for _, sub := range types {
+		part := tsType(sub, indent)
+		if !seen[part] {
+			seen[part] = true
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, " | ")
+}
+
+func tsObject(t *Type, indent string) string {
+	b := &strings.Builder{}
+	b.WriteString("{\n")
+	inner := indent + "  "
+	for _, key := range t.Properties.Keys() {
+		v, _ := t.Properties.Get(key)
+		p, ok := v.(*Type)
+		if !ok {
+			continue
+		}
+		tsComment(b, p, inner)
+		optional := "?"
+		if contains(t.Required, key) {
+			optional = ""
+		}
+		fmt.Fprintf(b, "%s%s%s: %s;\n", inner, tsPropertyName(key), optional, tsType(p, inner))
+	}
+	b.WriteString(indent + "}")
+	return b.String()
+}
+
+func tsComment(b *strings.Builder, t *Type, indent string) {
+	text := t.Description
+	if text == "" {
+		text = t.Title
+	}
+	if text == "" {
+		return
+	}
+	text = strings.ReplaceAll(text, "*/", "* /")
+	lines := strings.Split(text, "\n")
+	if len(lines) == 1 {
+		fmt.Fprintf(b, "%s/** %s */\n", indent, text)
+		return
+	}
+	fmt.Fprintf(b, "%s/**\n", indent)
+	for _, line := range lines {
+		fmt.Fprintf(b, "%s * %s\n", indent, line)
+	}
+	fmt.Fprintf(b, "%s */\n", indent)
+}
+
+// tsParen wraps unions and intersections so they can be used as array
+// elements or intersection members.
+func tsParen(s string) string {
+	depth := 0
+	for i, r := range s {
+		switch r {
+		case '{', '(', '[', '<':
+			depth++
+		case '}', ')', ']', '>':
+			depth--
+		case '|', '&':
+			if depth == 0 && i > 0 && s[i-1] == ' ' {
+				return "(" + s + ")"
+			}
+		}
+	}
+	return s
+}
+
+var (
+	tsIdentifierPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+	tsInvalidIdentifier = regexp.MustCompile(`[^A-Za-z0-9_$]+`)
+)
+
+func tsPropertyName(name string) string {
+	if tsIdentifierPattern.MatchString(name) {
+		return name
+	}
+	b, _ := json.Marshal(name)
+	return string(b)
+}
+
+// tsIdentifier turns a definition name, which may be fully qualified, into a
+// valid TypeScript identifier.
+func tsIdentifier(name string) string {
+	name = tsInvalidIdentifier.ReplaceAllString(name, "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+	return name
+}
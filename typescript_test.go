@@ -0,0 +1,40 @@
+package jsonschema
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type TSOrder struct {
+	ID       string            `json:"id" jsonschema:"description=unique order id"`
+	Status   string            `json:"status" jsonschema:"enum=pending,enum=shipped"`
+	Items    []TSOrderItem     `json:"items"`
+	Notes    []string          `json:"notes,omitempty" jsonschema:"nullable"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+	Counts   map[int]float64   `json:"counts,omitempty"`
+	Extra    interface{}       `json:"extra,omitempty" jsonschema:"oneof_type=string;integer"`
+	Shipping *TSOrderItem      `json:"x-shipping,omitempty"`
+}
+
+type TSOrderItem struct {
+	SKU      string `json:"sku"`
+	Quantity int    `json:"quantity,omitempty"`
+}
+
+func TestWriteTypeScript(t *testing.T) {
+	b := &bytes.Buffer{}
+	require.NoError(t, WriteTypeScript(b, Reflect(&TSOrder{})))
+	expected, err := ioutil.ReadFile("fixtures/typescript.d.ts")
+	require.NoError(t, err)
+	require.Equal(t, string(expected), b.String())
+}
+
+func TestTSParen(t *testing.T) {
+	require.Equal(t, "string", tsParen("string"))
+	require.Equal(t, "(string | null)", tsParen("string | null"))
+	require.Equal(t, "Record<string, string | null>", tsParen("Record<string, string | null>"))
+	require.Equal(t, "({\n  a: string | null;\n} | null)", tsParen("{\n  a: string | null;\n} | null"))
+}
@@ -0,0 +1,92 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"reflect"
+	"sync"
+)
+
+// UnmarshalOption configures Unmarshal.
+type UnmarshalOption func(*unmarshalOptions)
+
+type unmarshalOptions struct {
+	reflector   *Reflector
+	defaults    bool
+	maxBodySize int64
+}
+
+// DefaultMaxBodySize is the size, in bytes, above which ValidateRequest
+// rejects request bodies unless WithMaxBodySize says otherwise.
+const DefaultMaxBodySize = 1 << 20
+
+// WithReflector reflects schemas with r instead of the default Reflector.
+// Schemas are cached per Reflector, so r should not be modified afterwards.
+func WithReflector(r *Reflector) UnmarshalOption {
+	return func(o *unmarshalOptions) {
+		o.reflector = r
+	}
+}
+
+// WithDefaults inserts the schema's defaults for missing properties before
+// the document is validated and decoded.
+func WithDefaults() UnmarshalOption {
+	return func(o *unmarshalOptions) {
+		o.defaults = true
+	}
+}
+
+// WithMaxBodySize limits the request bodies read by ValidateRequest to n
+// bytes, instead of DefaultMaxBodySize. Unmarshal ignores it.
+func WithMaxBodySize(n int64) UnmarshalOption {
+	return func(o *unmarshalOptions) {
+		o.maxBodySize = n
+	}
+}
+
+var (
+	defaultReflector = &Reflector{}
+	schemaCache      sync.Map
+)
+
+type schemaCacheKey struct {
+	reflector *Reflector
+	t         reflect.Type
+}
+
+// Unmarshal validates data against the schema reflected from the type of v
+// and, if it is valid, decodes it into v with encoding/json.
+//
+// The schema is reflected once per type and cached. Documents that do not
+// satisfy the schema are reported with a *ValidationError, and v is left
+// untouched.
+func Unmarshal(data []byte, v interface{}, opts ...UnmarshalOption) error {
+	o := &unmarshalOptions{reflector: defaultReflector}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if rv := reflect.ValueOf(v); rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return &json.InvalidUnmarshalError{Type: reflect.TypeOf(v)}
+	}
+	schema := cachedSchema(o.reflector, reflect.TypeOf(v))
+	if o.defaults {
+		var err error
+		data, err = ApplyDefaultsJSON(data, schema)
+		if err != nil {
+			return err
+		}
+	}
+	if err := Validate(schema, data); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// cachedSchema reflects t with r, once.
+func cachedSchema(r *Reflector, t reflect.Type) *Schema {
+	key := schemaCacheKey{r, t}
+	if s, ok := schemaCache.Load(key); ok {
+		return s.(*Schema)
+	}
+	s, _ := schemaCache.LoadOrStore(key, r.ReflectFromType(t))
+	return s.(*Schema)
+}
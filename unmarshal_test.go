@@ -0,0 +1,36 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type UnmarshalConfig struct {
+	Host string `json:"host" jsonschema:"minLength=1"`
+	Port int    `json:"port,omitempty" jsonschema:"minimum=1,maximum=65535,default=8080"`
+}
+
+func TestUnmarshal(t *testing.T) {
+	var c UnmarshalConfig
+	require.NoError(t, Unmarshal([]byte(`{"host": "localhost", "port": 80}`), &c))
+	require.Equal(t, UnmarshalConfig{Host: "localhost", Port: 80}, c)
+
+	c = UnmarshalConfig{}
+	err := Unmarshal([]byte(`{"host": "", "port": 0, "debug": true}`), &c)
+	require.IsType(t, &ValidationError{}, err)
+	require.Len(t, err.(*ValidationError).Problems, 3)
+	require.Equal(t, UnmarshalConfig{}, c, "invalid documents are not decoded")
+
+	require.NoError(t, Unmarshal([]byte(`{"host": "localhost"}`), &c, WithDefaults()))
+	require.Equal(t, UnmarshalConfig{Host: "localhost", Port: 8080}, c)
+
+	r := &Reflector{AllowAdditionalProperties: true}
+	require.NoError(t, Unmarshal([]byte(`{"host": "localhost", "debug": true}`), &c, WithReflector(r)))
+	require.Same(t, cachedSchema(r, reflect.TypeOf(&c)), cachedSchema(r, reflect.TypeOf(&c)))
+
+	require.IsType(t, &json.InvalidUnmarshalError{}, Unmarshal([]byte(`{}`), c))
+	require.IsType(t, &json.InvalidUnmarshalError{}, Unmarshal([]byte(`{}`), nil))
+}
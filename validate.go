@@ -0,0 +1,588 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ValidationError lists the ways a document does not satisfy a schema.
+type ValidationError struct {
+	Problems []*ValidationProblem
+}
+
+// ValidationProblem is a single keyword a document fails to satisfy.
+type ValidationProblem struct {
+	// Path is the JSON Pointer of the offending value within the document,
+	// which is empty for the document itself.
+	Path string
+	// Keyword is the schema keyword that failed, such as "required".
+	Keyword string
+	// Message describes the problem.
+	Message string
+}
+
+func (p *ValidationProblem) String() string {
+	if p.Path == "" {
+		return p.Message
+	}
+	return p.Path + ": " + p.Message
+}
+
+func (e *ValidationError) Error() string {
+	lines := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		lines[i] = p.String()
+	}
+	return "jsonschema: document does not match schema:\n  " + strings.Join(lines, "\n  ")
+}
+
+// A Validator validates documents against schemas, loading the schemas that
+// $refs to other documents point to with its Loader.
+type Validator struct {
+	// Loader loads referenced schemas by URI. Defaults to MetaSchemas.
+	Loader Loader
+}
+
+// Validate checks the JSON document doc against s using the default
+// Validator.
+func Validate(s *Schema, doc []byte) error {
+	return (&Validator{}).Validate(s, doc)
+}
+
+// ValidateValue checks v, a JSON value as decoded by encoding/json into an
+// interface{}, against s using the default Validator.
+func ValidateValue(s *Schema, v interface{}) error {
+	return (&Validator{}).ValidateValue(s, v)
+}
+
+// Validate checks the JSON document doc against s. It returns a
+// *ValidationError listing every problem found, or an error if doc is not
+// valid JSON.
+//
+// The type, enum, numeric, string, array, object and combining keywords are
+// checked, along with the date-time, date, email, hostname, ipv4, ipv6, uri
+// and uuid formats. Keywords kept in Extras because Type cannot hold them,
+// such as a fractional multipleOf or the array form of items, are checked
+// too, and reported as unsupported if their value cannot be enforced. As
+// elsewhere in this package, zero limits such as a minimum of 0 are treated
+// as absent, unless kept in Extras by parsing.
+//
+// References are resolved against the $id of s, or its draft-04 id.
+func (vr *Validator) Validate(s *Schema, doc []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return errors.New("jsonschema: unexpected data after the JSON document")
+	}
+	return vr.ValidateValue(s, v)
+}
+
+// ValidateValue checks v, a JSON value as decoded by encoding/json into an
+// interface{}, against s.
+func (vr *Validator) ValidateValue(s *Schema, v interface{}) error {
+	loader := vr.Loader
+	if loader == nil {
+		loader = MetaSchemas
+	}
+	val := &validator{
+		loader:   loader,
+		docs:     map[string]*Schema{},
+		patterns: map[string]*regexp.Regexp{},
+		schema:   s,
+		base:     schemaID(s.Type),
+	}
+	if problems := val.validate(s.Type, v, ""); len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// validator validates values against the schemas of one document.
+type validator struct {
+	loader   Loader
+	docs     map[string]*Schema
+	patterns map[string]*regexp.Regexp
+	schema   *Schema
+	base     string
+}
+
+func (val *validator) validate(t *Type, v interface{}, path string) []*ValidationProblem {
+	var problems []*ValidationProblem
+	fail := func(keyword, format string, args ...interface{}) {
+		problems = append(problems, &ValidationProblem{
+			Path:    path,
+			Keyword: keyword,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	if t.Ref != "" {
+		def, in, err := val.resolveRef(t.Ref)
+		if err != nil {
+			fail("$ref", "%v", err)
+			return problems
+		}
+		return in.validate(def, v, path)
+	}
+
+	kind := jsonKind(v)
+	if t.Type != "" && t.Type != kind && !(t.Type == "number" && kind == "integer") {
+		fail("type", "expected %s, got %s", t.Type, kind)
+		return problems
+	}
+	if len(t.Enum) > 0 {
+		found := false
+		for _, e := range t.Enum {
+			if canonicalJSON(e) == canonicalJSON(v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fail("enum", "must be one of %s", docJSONList(t.Enum))
+		}
+	}
+
+	switch kind {
+	case "integer", "number":
+		val.number(t, jsonFloat(v), fail)
+	case "string":
+		val.string(t, v.(string), fail)
+	case "array":
+		problems = append(problems, val.array(t, v.([]interface{}), path, fail)...)
+	case "object":
+		nested := val.object(t, v.(map[string]interface{}), path, fail)
+		problems = append(problems, nested...)
+	}
+
+	for _, sub := range t.AllOf {
+		problems = append(problems, val.validate(sub, v, path)...)
+	}
+	if len(t.AnyOf) > 0 {
+		matched := false
+		for _, sub := range t.AnyOf {
+			if len(val.validate(sub, v, path)) == 0 {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			fail("anyOf", "must match at least one schema in anyOf")
+		}
+	}
+	if len(t.OneOf) > 0 {
+		matched := 0
+		for _, sub := range t.OneOf {
+			if len(val.validate(sub, v, path)) == 0 {
+				matched++
+			}
+		}
+		if matched != 1 {
+			fail("oneOf", "must match exactly one schema in oneOf, matched %d", matched)
+		}
+	}
+	if t.Not != nil && len(val.validate(t.Not, v, path)) == 0 {
+		fail("not", "must not match the schema in not")
+	}
+	return problems
+}
+
+// resolveRef returns the schema ref points to, following references to
+// references, and the validator for the document it is in.
+func (val *validator) resolveRef(ref string) (*Type, *validator, error) {
+	in := val
+	for hops := 0; hops < 32; hops++ {
+		t, next, err := in.resolveRefOnce(ref)
+		if err != nil {
+			return nil, nil, err
+		}
+		if t.Ref == "" {
+			return t, next, nil
+		}
+		ref, in = t.Ref, next
+	}
+	return nil, nil, fmt.Errorf("too many references resolving $ref %s", ref)
+}
+
+func (val *validator) resolveRefOnce(ref string) (*Type, *validator, error) {
+	uri, fragment := ref, ""
+	if i := strings.Index(ref, "#"); i >= 0 {
+		uri, fragment = ref[:i], ref[i+1:]
+	}
+	in := val
+	if uri != "" {
+		abs, err := resolveURI(val.base, uri)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid $ref %s: %w", ref, err)
+		}
+		s, ok := val.docs[abs]
+		if !ok {
+			if s, err = val.loader.Load(abs); err != nil {
+				return nil, nil, fmt.Errorf("cannot load $ref %s: %w", ref, err)
+			}
+			val.docs[abs] = s
+		}
+		in = &validator{loader: val.loader, docs: val.docs, patterns: val.patterns, schema: s, base: abs}
+	}
+	switch {
+	case fragment == "" || fragment == "/":
+		if in.schema.Type != nil {
+			return in.schema.Type, in, nil
+		}
+	case strings.HasPrefix(fragment, "/definitions/"):
+		name := pointerUnescape(strings.TrimPrefix(fragment, "/definitions/"))
+		if def := in.schema.Definitions[name]; def != nil {
+			return def, in, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("unresolvable $ref %s", ref)
+}
+
+// schemaID returns the $id of t, or its draft-04 id, without fragment.
+func schemaID(t *Type) string {
+	if t == nil {
+		return ""
+	}
+	id := t.ID
+	if s, ok := t.Extras["id"].(string); ok && id == "" {
+		id = s
+	}
+	return trimFragment(id)
+}
+
+func (val *validator) number(t *Type, f float64, fail func(keyword, format string, args ...interface{})) {
+	if m, ok := limit(t, "multipleOf", t.MultipleOf, fail); ok && m > 0 {
+		if q := f / m; math.Abs(q-math.Round(q)) > 1e-9 {
+			fail("multipleOf", "must be a multiple of %v", m)
+		}
+	}
+	min, hasMin := extraLimit(t, "minimum", fail)
+	if t.Minimum != 0 || t.ExclusiveMinimum || hasMin {
+		if t.Minimum != 0 {
+			min = float64(t.Minimum)
+		}
+		if t.ExclusiveMinimum && f <= min {
+			fail("minimum", "must be greater than %v", min)
+		} else if f < min {
+			fail("minimum", "must be at least %v", min)
+		}
+	}
+	max, hasMax := extraLimit(t, "maximum", fail)
+	if t.Maximum != 0 || t.ExclusiveMaximum || hasMax {
+		if t.Maximum != 0 {
+			max = float64(t.Maximum)
+		}
+		if t.ExclusiveMaximum && f >= max {
+			fail("maximum", "must be less than %v", max)
+		} else if f > max {
+			fail("maximum", "must be at most %v", max)
+		}
+	}
+}
+
+func (val *validator) string(t *Type, s string, fail func(keyword, format string, args ...interface{})) {
+	n := float64(utf8.RuneCountInString(s))
+	if min, ok := limit(t, "minLength", t.MinLength, fail); ok && n < min {
+		fail("minLength", "must be at least %v characters long", min)
+	}
+	if max, ok := limit(t, "maxLength", t.MaxLength, fail); ok && n > max {
+		fail("maxLength", "must be at most %v characters long", max)
+	}
+	if t.Pattern != "" {
+		re, err := val.pattern(t.Pattern)
+		if err != nil {
+			fail("pattern", "invalid pattern %q in schema", t.Pattern)
+		} else if !re.MatchString(s) {
+			fail("pattern", "must match pattern %q", t.Pattern)
+		}
+	}
+	if t.Format != "" && !validFormat(t.Format, s) {
+		fail("format", "must be a valid %s", t.Format)
+	}
+}
+
+func (val *validator) array(t *Type, items []interface{}, path string, fail func(keyword, format string, args ...interface{})) []*ValidationProblem {
+	var problems []*ValidationProblem
+	n := float64(len(items))
+	if min, ok := limit(t, "minItems", t.MinItems, fail); ok && n < min {
+		fail("minItems", "must have at least %v items", min)
+	}
+	if max, ok := limit(t, "maxItems", t.MaxItems, fail); ok && n > max {
+		fail("maxItems", "must have at most %v items", max)
+	}
+	if t.UniqueItems {
+		seen := map[string]int{}
+		for i, item := range items {
+			key := canonicalJSON(item)
+			if j, ok := seen[key]; ok {
+				fail("uniqueItems", "items %d and %d are equal", j, i)
+				break
+			}
+			seen[key] = i
+		}
+	}
+	if t.Items != nil {
+		for i, item := range items {
+			problems = append(problems, val.validate(t.Items, item, path+"/"+strconv.Itoa(i))...)
+		}
+		return problems
+	}
+
+	// The array form of items, kept in Extras by parsing, describes each
+	// item in turn, and additionalItems the ones after them.
+	if _, ok := t.Extras["items"]; !ok {
+		return problems
+	}
+	var tuple []*Type
+	if err := remarshal(t.Extras["items"], &tuple); err != nil {
+		fail("items", "unsupported items %s in schema", canonicalJSON(t.Extras["items"]))
+		return problems
+	}
+	for i, item := range items {
+		switch {
+		case i < len(tuple):
+			problems = append(problems, val.validate(tuple[i], item, path+"/"+strconv.Itoa(i))...)
+		case t.AdditionalItems != nil:
+			problems = append(problems, val.validate(t.AdditionalItems, item, path+"/"+strconv.Itoa(i))...)
+		}
+	}
+	if additional, ok := t.Extras["additionalItems"]; ok && len(items) > len(tuple) {
+		if additional == false {
+			fail("additionalItems", "must have at most %d items", len(tuple))
+		} else if additional != true {
+			fail("additionalItems", "unsupported additionalItems %s in schema", canonicalJSON(additional))
+		}
+	}
+	return problems
+}
+
+func (val *validator) object(t *Type, obj map[string]interface{}, path string, fail func(keyword, format string, args ...interface{})) []*ValidationProblem {
+	var problems []*ValidationProblem
+	n := float64(len(obj))
+	if min, ok := limit(t, "minProperties", t.MinProperties, fail); ok && n < min {
+		fail("minProperties", "must have at least %v properties", min)
+	}
+	if max, ok := limit(t, "maxProperties", t.MaxProperties, fail); ok && n > max {
+		fail("maxProperties", "must have at most %v properties", max)
+	}
+	for _, key := range t.Required {
+		if _, ok := obj[key]; !ok {
+			fail("required", "missing required property %q", key)
+		}
+	}
+
+	var additional *Type
+	allowAdditional := true
+	switch raw := bytes.TrimSpace(t.AdditionalProperties); {
+	case string(raw) == "false":
+		allowAdditional = false
+	case len(raw) > 0 && raw[0] == '{':
+		additional = &Type{}
+		if err := json.Unmarshal(raw, additional); err != nil {
+			fail("additionalProperties", "invalid additionalProperties in schema: %v", err)
+			additional = nil
+		}
+	}
+
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := obj[key]
+		keyPath := path + "/" + pointerEscape(key)
+		matched := false
+		if t.Properties != nil {
+			if p, ok := t.Properties.Get(key); ok {
+				matched = true
+				if sub, ok := p.(*Type); ok {
+					problems = append(problems, val.validate(sub, value, keyPath)...)
+				}
+			}
+		}
+		for _, pattern := range sortedKeys(t.PatternProperties) {
+			re, err := val.pattern(pattern)
+			if err != nil || !re.MatchString(key) {
+				continue
+			}
+			matched = true
+			problems = append(problems, val.validate(t.PatternProperties[pattern], value, keyPath)...)
+		}
+		switch {
+		case matched:
+		case !allowAdditional:
+			fail("additionalProperties", "unexpected property %q", key)
+		case additional != nil:
+			problems = append(problems, val.validate(additional, value, keyPath)...)
+		}
+	}
+
+	for _, key := range sortedKeys(t.Dependencies) {
+		if _, ok := obj[key]; ok {
+			problems = append(problems, val.validate(t.Dependencies[key], obj, path)...)
+		}
+	}
+	return problems
+}
+
+func (val *validator) pattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := val.patterns[pattern]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	val.patterns[pattern] = re
+	return re, nil
+}
+
+var (
+	hostnamePattern = regexp.MustCompile(`^(?i)[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?(\.[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?)*$`)
+	uuidPattern     = regexp.MustCompile(`^(?i)[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+)
+
+// validFormat reports whether s is valid for format. Unknown formats are
+// always valid.
+func validFormat(format, s string) bool {
+	switch format {
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, s)
+		return err == nil
+	case "date":
+		_, err := time.Parse("2006-01-02", s)
+		return err == nil
+	case "email":
+		addr, err := mail.ParseAddress(s)
+		return err == nil && addr.Address == s
+	case "hostname":
+		return len(s) <= 253 && hostnamePattern.MatchString(s)
+	case "ipv4":
+		ip := net.ParseIP(s)
+		return ip != nil && strings.Contains(s, ".") && !strings.Contains(s, ":")
+	case "ipv6":
+		return net.ParseIP(s) != nil && strings.Contains(s, ":")
+	case "uri":
+		u, err := url.Parse(s)
+		return err == nil && u.IsAbs()
+	case "uuid":
+		return uuidPattern.MatchString(s)
+	}
+	return true
+}
+
+// jsonKind returns the JSON Schema type of a decoded JSON value.
+func jsonKind(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number, float64:
+		if f := jsonFloat(v); f == math.Trunc(f) && !math.IsInf(f, 0) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// limit returns the value of the numeric keyword name, from field or, when
+// the field cannot hold it, from Extras.
+func limit(t *Type, name string, field int, fail func(keyword, format string, args ...interface{})) (float64, bool) {
+	if field != 0 {
+		return float64(field), true
+	}
+	return extraLimit(t, name, fail)
+}
+
+// extraLimit returns the numeric keyword name kept in Extras, such as a
+// fractional multipleOf from parsing or a minimum from a jsonschema_extras
+// tag, and fails for values that cannot be enforced.
+func extraLimit(t *Type, name string, fail func(keyword, format string, args ...interface{})) (float64, bool) {
+	v, ok := t.Extras[name]
+	if !ok {
+		return 0, false
+	}
+	if f, ok := extraNumber(t, name); ok {
+		return f, true
+	}
+	if s, ok := v.(string); ok {
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f, true
+		}
+	}
+	fail(name, "unsupported %s %s in schema", name, canonicalJSON(v))
+	return 0, false
+}
+
+// remarshal converts v, as decoded into an interface{}, into out.
+func remarshal(v interface{}, out interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, out)
+}
+
+// extraNumber returns the numeric keyword name from the Extras of t, where
+// parsing keeps keywords whose value is 0.
+func extraNumber(t *Type, name string) (float64, bool) {
+	switch v := t.Extras[name].(type) {
+	case int:
+		return float64(v), true
+	case float64, json.Number:
+		return jsonFloat(v), true
+	}
+	return 0, false
+}
+
+func jsonFloat(v interface{}) float64 {
+	switch v := v.(type) {
+	case json.Number:
+		f, _ := v.Float64()
+		return f
+	case float64:
+		return v
+	}
+	return 0
+}
+
+// canonicalJSON encodes v so that equal JSON values have equal encodings,
+// whatever the Go types they were decoded into.
+func canonicalJSON(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		return string(b)
+	}
+	b, _ = json.Marshal(decoded)
+	return string(b)
+}
@@ -0,0 +1,154 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type ValidateAddress struct {
+	Street string `json:"street" jsonschema:"minLength=1"`
+	Zip    string `json:"zip" jsonschema:"pattern=^[0-9]{5}$"`
+}
+
+type ValidateUser struct {
+	Name     string            `json:"name" jsonschema:"minLength=2,maxLength=10"`
+	Age      int               `json:"age" jsonschema:"minimum=18,maximum=99"`
+	Score    float64           `json:"score,omitempty" jsonschema:"maximum=10,exclusiveMaximum=true"`
+	Email    string            `json:"email,omitempty" jsonschema:"format=email"`
+	Role     string            `json:"role" jsonschema:"enum=admin,enum=user"`
+	Tags     []string          `json:"tags,omitempty" jsonschema:"maxItems=2,uniqueItems=true"`
+	Address  *ValidateAddress  `json:"address,omitempty"`
+	Labels   map[string]int    `json:"labels,omitempty"`
+	Nickname string            `json:"nickname,omitempty" jsonschema:"nullable"`
+	Extra    map[string]string `json:"-"`
+}
+
+func TestValidate(t *testing.T) {
+	schema := Reflect(&ValidateUser{})
+	valid := `{"name": "joe", "age": 18, "score": 9.5, "email": "joe@example.com", "role": "user", "tags": ["a", "b"], "address": {"street": "Main", "zip": "12345"}, "labels": {"x": 1}, "nickname": null}`
+	require.NoError(t, Validate(schema, []byte(valid)))
+
+	err := Validate(schema, []byte(`{
+		"name": "j",
+		"age": 100,
+		"score": 10,
+		"email": "joe",
+		"role": "root",
+		"tags": ["a", "a", "b"],
+		"address": {"street": "", "zip": "1234"},
+		"labels": {"x": "1"},
+		"nickname": 1,
+		"unknown": true
+	}`))
+	require.IsType(t, &ValidationError{}, err)
+	var problems []string
+	for _, p := range err.(*ValidationError).Problems {
+		problems = append(problems, p.Keyword+" "+p.String())
+	}
+	require.Equal(t, "", err.(*ValidationError).Problems[0].Path, "the document is the empty pointer")
+	require.Equal(t, []string{
+		`additionalProperties unexpected property "unknown"`,
+		`minLength /address/street: must be at least 1 characters long`,
+		`pattern /address/zip: must match pattern "^[0-9]{5}$"`,
+		`maximum /age: must be at most 99`,
+		`format /email: must be a valid email`,
+		`type /labels/x: expected integer, got string`,
+		`minLength /name: must be at least 2 characters long`,
+		`oneOf /nickname: must match exactly one schema in oneOf, matched 0`,
+		`enum /role: must be one of "admin", "user"`,
+		`maximum /score: must be less than 10`,
+		`maxItems /tags: must have at most 2 items`,
+		`uniqueItems /tags: items 0 and 1 are equal`,
+	}, problems)
+
+	err = Validate(schema, []byte(`{"age": 20.5}`))
+	require.Contains(t, err.Error(), "\n  missing required property \"name\"")
+	require.Contains(t, err.Error(), `/age: expected integer, got number`)
+
+	require.Error(t, Validate(schema, []byte(`{"name": "joe"`)))
+	require.Error(t, Validate(schema, []byte(`{} {}`)))
+}
+
+func TestValidFormat(t *testing.T) {
+	for _, test := range []struct {
+		format, value string
+		valid         bool
+	}{
+		{"date-time", "2021-01-02T15:04:05Z", true},
+		{"date-time", "2021-01-02", false},
+		{"date", "2021-01-02", true},
+		{"email", "Joe <joe@example.com>", false},
+		{"hostname", "example.com", true},
+		{"hostname", "-example.com", false},
+		{"ipv4", "127.0.0.1", true},
+		{"ipv4", "::1", false},
+		{"ipv6", "::1", true},
+		{"uri", "https://example.com/x", true},
+		{"uri", "/x", false},
+		{"uuid", "123e4567-e89b-42d3-a456-426614174000", true},
+		{"uuid", "123e4567", false},
+		{"unknown", "anything", true},
+	} {
+		require.Equal(t, test.valid, validFormat(test.format, test.value), "%s %q", test.format, test.value)
+	}
+}
+
+func TestValidateCombinators(t *testing.T) {
+	schema := &Schema{
+		Type: &Type{
+			AllOf: []*Type{{Type: "integer"}, {Ref: "#/definitions/Positive"}},
+			Not:   &Type{Enum: []interface{}{13}},
+			AnyOf: []*Type{{MultipleOf: 2}, {MultipleOf: 3}},
+		},
+		Definitions: Definitions{"Positive": {Minimum: 1}},
+	}
+	require.NoError(t, Validate(schema, []byte(`4`)))
+	require.NoError(t, Validate(schema, []byte(`9`)))
+	require.EqualError(t, Validate(schema, []byte(`13`)), "jsonschema: document does not match schema:\n"+
+		"  must match at least one schema in anyOf\n"+
+		"  must not match the schema in not")
+	require.EqualError(t, Validate(schema, []byte(`-2`)), "jsonschema: document does not match schema:\n"+
+		"  must be at least 1")
+	require.Error(t, Validate(&Schema{Type: &Type{Ref: "#/definitions/Missing"}}, []byte(`1`)))
+}
+
+func TestValidateParsedKeywords(t *testing.T) {
+	for _, tt := range []struct {
+		schema string
+		doc    string
+		err    string
+	}{
+		{`{"type": "number", "multipleOf": 0.5}`, `1.5`, ""},
+		{`{"type": "number", "multipleOf": 0.5}`, `0.3`, "must be a multiple of 0.5"},
+		{`{"type": "string", "minLength": 2.0, "maxLength": 3.0}`, `"ab"`, ""},
+		{`{"type": "string", "minLength": 2.0}`, `"a"`, "must be at least 2 characters long"},
+		{`{"type": "string", "maxLength": 3.0}`, `"abcd"`, "must be at most 3 characters long"},
+		{`{"type": "array", "minItems": 2.0}`, `[1]`, "must have at least 2 items"},
+		{`{"type": "array", "maxItems": 1.0}`, `[1, 2]`, "must have at most 1 items"},
+		{`{"type": "object", "maxProperties": 1.0}`, `{"a": 1, "b": 2}`, "must have at most 1 properties"},
+		{`{"items": [{"type": "string"}, {"type": "integer"}]}`, `["a", 1, true]`, ""},
+		{`{"items": [{"type": "string"}, {"type": "integer"}]}`, `[1]`, "/0: expected string, got integer"},
+		{`{"items": [{"type": "string"}], "additionalItems": false}`, `["a", "b"]`, "must have at most 1 items"},
+		{`{"items": [{"type": "string"}], "additionalItems": {"type": "integer"}}`, `["a", "b"]`, "/1: expected integer, got string"},
+		{`{"type": "number", "multipleOf": "half"}`, `1`, `unsupported multipleOf "half" in schema`},
+		{`{"type": "array", "items": [1]}`, `[1]`, "unsupported items [1] in schema"},
+	} {
+		schema := &Schema{}
+		require.NoError(t, json.Unmarshal([]byte(tt.schema), schema), tt.schema)
+		err := Validate(schema, []byte(tt.doc))
+		if tt.err == "" {
+			require.NoError(t, err, tt.schema)
+		} else {
+			require.EqualError(t, err, "jsonschema: document does not match schema:\n  "+tt.err, tt.schema)
+		}
+	}
+}
+
+func TestValidateQuick(t *testing.T) {
+	schema := Reflect(&ValidateUser{})
+	Quick(t, &ValidateUser{}, func(doc []byte) error {
+		return Validate(schema, doc)
+	})
+}
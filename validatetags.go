@@ -0,0 +1,284 @@
+package jsonschema
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// validatePatterns holds the patterns implied by go-playground/validator
+// string rules.
+var validatePatterns = map[string]string{
+	"alpha":    "^[a-zA-Z]+$",
+	"alphanum": "^[a-zA-Z0-9]+$",
+	"numeric":  "^[-+]?[0-9]+(?:\\.[0-9]+)?$",
+}
+
+// validateFormats maps go-playground/validator string rules to formats.
+var validateFormats = map[string]string{
+	"email":            "email",
+	"hostname":         "hostname",
+	"hostname_rfc1123": "hostname",
+	"ipv4":             "ipv4",
+	"ipv6":             "ipv6",
+	"uri":              "uri",
+	"url":              "uri",
+	"http_url":         "uri",
+	"uuid":             "uuid",
+	"uuid3":            "uuid",
+	"uuid4":            "uuid",
+	"uuid5":            "uuid",
+}
+
+// validateNoOps holds go-playground/validator rules that do not constrain
+// the JSON value of a field.
+var validateNoOps = map[string]bool{
+	"omitempty":     true,
+	"omitnil":       true,
+	"structonly":    true,
+	"nostructlevel": true,
+}
+
+// validateOneOfValue matches a single value of a oneof rule, which may be
+// single-quoted to hold spaces, as go-playground/validator splits them.
+var validateOneOfValue = regexp.MustCompile(`'[^']*'|\S+`)
+
+// validateTagKeywords sets the keywords of t implied by the
+// go-playground/validator rules in the ValidatorTags of f, such as
+// `validate:"min=1,max=20"`, unless the jsonschema tag of f sets them. It
+// reports whether the rules require the field.
+//
+// Rules after "dive" apply to the items of arrays. Alternatives joined with
+// "|" are translated when they all imply the same keywords. Rules without a
+// counterpart are reported as errors.
+func (r *Reflector) validateTagKeywords(t *Type, f reflect.StructField) (bool, error) {
+	explicit := map[string]bool{}
+	for _, tag := range strings.Split(f.Tag.Get("jsonschema"), ",") {
+		explicit[strings.SplitN(tag, "=", 2)[0]] = true
+	}
+	required := false
+	for _, key := range r.ValidatorTags {
+		tag, ok := f.Tag.Lookup(key)
+		if !ok {
+			continue
+		}
+		target, keywords := t, explicit
+		for _, rule := range strings.Split(tag, ",") {
+			var err error
+			switch {
+			case rule == "" || validateNoOps[rule]:
+			case rule == "dive":
+				if target.Type != "array" || target.Items == nil {
+					err = fmt.Errorf("dive only applies to arrays, not %s", validateTypeName(target))
+					break
+				}
+				// The enum of a jsonschema tag on an array applies to its
+				// items.
+				target, keywords = target.Items, map[string]bool{"enum": explicit["enum"]}
+			case rule == "required" && target == t:
+				required = true
+			case strings.Contains(rule, "|"):
+				err = target.validateAlternatives(strings.Split(rule, "|"), keywords)
+			default:
+				err = target.validateRule(rule, keywords)
+			}
+			if err != nil {
+				return false, fmt.Errorf("cannot translate %s rule %q: %v", key, rule, err)
+			}
+		}
+	}
+	return required, nil
+}
+
+// validateAlternatives sets the keywords of t implied by rules, one of which
+// must hold, if they all imply the same keywords, such as url|uri.
+func (t *Type) validateAlternatives(rules []string, explicit map[string]bool) error {
+	var first *Type
+	for _, rule := range rules {
+		alt := t.clone()
+		alt.Extras = map[string]interface{}{}
+		for k, v := range t.Extras {
+			alt.Extras[k] = v
+		}
+		if err := alt.validateRule(rule, explicit); err != nil {
+			return err
+		}
+		if first == nil {
+			first = alt
+		} else if !reflect.DeepEqual(first, alt) {
+			return fmt.Errorf("alternatives %s and %s imply different keywords", rules[0], rule)
+		}
+	}
+	if len(first.Extras) == 0 {
+		first.Extras = t.Extras
+	}
+	*t = *first
+	return nil
+}
+
+// validateRule sets the keywords of t implied by a single
+// go-playground/validator rule, skipping those in explicit.
+func (t *Type) validateRule(rule string, explicit map[string]bool) error {
+	name, value := rule, ""
+	if i := strings.Index(rule, "="); i >= 0 {
+		name, value = rule[:i], rule[i+1:]
+	}
+	set := func(keyword string, apply func()) {
+		if !explicit[keyword] {
+			apply()
+		}
+	}
+	unsupported := fmt.Errorf("no keyword corresponds to it for %s", validateTypeName(t))
+
+	switch name {
+	case "min", "gte", "gt", "max", "lte", "lt", "len":
+		if t.Type == "integer" || t.Type == "number" {
+			return t.validateBound(name, value, set)
+		}
+		minField, maxField, minKeyword, maxKeyword := t.lengthLimits()
+		if minField == nil || t.Format == "date-time" {
+			// For time.Time, these rules compare with the current time.
+			return unsupported
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("%q is not a length", value)
+		}
+		switch name {
+		case "gt":
+			n++
+		case "lt":
+			n--
+			if n < 0 {
+				return fmt.Errorf("no length is less than 0")
+			}
+		}
+		if name != "max" && name != "lte" && name != "lt" {
+			set(minKeyword, func() { t.setBound(minKeyword, minField, n) })
+		}
+		if name != "min" && name != "gte" && name != "gt" {
+			set(maxKeyword, func() { t.setBound(maxKeyword, maxField, n) })
+		}
+	case "oneof":
+		return t.validateOneOf(value, set)
+	case "unique":
+		if t.Type != "array" || value != "" {
+			return unsupported
+		}
+		set("uniqueItems", func() { t.UniqueItems = true })
+	default:
+		if t.Type != "string" || value != "" {
+			return unsupported
+		}
+		if pattern, ok := validatePatterns[name]; ok {
+			set("pattern", func() { t.Pattern = pattern })
+		} else if format, ok := validateFormats[name]; ok {
+			set("format", func() { t.Format = format })
+		} else {
+			return unsupported
+		}
+	}
+	return nil
+}
+
+// validateBound sets the minimum or maximum of a number implied by a rule
+// such as gt=0 or max=2.5.
+func (t *Type) validateBound(name, value string, set func(string, func())) error {
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fmt.Errorf("%q is not a number", value)
+	}
+	if name == "min" || name == "gte" || name == "gt" || name == "len" {
+		set("minimum", func() {
+			t.setNumericBound("minimum", &t.Minimum, f)
+			t.ExclusiveMinimum = name == "gt"
+		})
+	}
+	if name == "max" || name == "lte" || name == "lt" || name == "len" {
+		set("maximum", func() {
+			t.setNumericBound("maximum", &t.Maximum, f)
+			t.ExclusiveMaximum = name == "lt"
+		})
+	}
+	return nil
+}
+
+// validateOneOf sets the enum of t to the space-separated values of a oneof
+// rule.
+func (t *Type) validateOneOf(value string, set func(string, func())) error {
+	var enum []interface{}
+	for _, v := range validateOneOfValue.FindAllString(value, -1) {
+		v = strings.Trim(v, "'")
+		switch t.Type {
+		case "string":
+			enum = append(enum, v)
+		case "integer":
+			i, err := strconv.Atoi(v)
+			if err != nil {
+				return fmt.Errorf("%q is not an integer", v)
+			}
+			enum = append(enum, i)
+		case "number":
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return fmt.Errorf("%q is not a number", v)
+			}
+			enum = append(enum, f)
+		default:
+			return fmt.Errorf("no keyword corresponds to it for %s", validateTypeName(t))
+		}
+	}
+	set("enum", func() { t.Enum = enum })
+	return nil
+}
+
+// validateTypeName names the JSON type of t in errors.
+func validateTypeName(t *Type) string {
+	if t.Type == "" {
+		return "this type"
+	}
+	return t.Type
+}
+
+// lengthLimits returns the fields and keywords limiting the length of
+// strings, arrays or objects of type t, or nils for other types.
+func (t *Type) lengthLimits() (min, max *int, minKeyword, maxKeyword string) {
+	switch t.Type {
+	case "string":
+		return &t.MinLength, &t.MaxLength, "minLength", "maxLength"
+	case "array":
+		return &t.MinItems, &t.MaxItems, "minItems", "maxItems"
+	case "object":
+		return &t.MinProperties, &t.MaxProperties, "minProperties", "maxProperties"
+	}
+	return nil, nil, "", ""
+}
+
+// setNumericBound sets the numeric limit field to f, keeping limits the field
+// cannot hold, such as 2.5, in Extras instead.
+func (t *Type) setNumericBound(keyword string, field *int, f float64) {
+	if f == math.Trunc(f) && math.Abs(f) < math.MaxInt32 {
+		t.setBound(keyword, field, int(f))
+		return
+	}
+	*field = 0
+	if t.Extras == nil {
+		t.Extras = map[string]interface{}{}
+	}
+	t.Extras[keyword] = f
+}
+
+// setBound sets the numeric limit field, keeping a zero limit in Extras so
+// that it is not omitted.
+func (t *Type) setBound(keyword string, field *int, n int) {
+	*field = n
+	if n == 0 {
+		if t.Extras == nil {
+			t.Extras = map[string]interface{}{}
+		}
+		t.Extras[keyword] = 0
+	}
+}
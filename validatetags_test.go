@@ -0,0 +1,109 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type ValidateTagsUser struct {
+	Name     string            `json:"name,omitempty" validate:"required,min=1,max=20"`
+	Email    string            `json:"email" validate:"required,email"`
+	Color    string            `json:"color,omitempty" validate:"oneof=red green"`
+	Code     string            `json:"code,omitempty" validate:"len=3,alpha"`
+	Website  string            `json:"website,omitempty" binding:"omitempty,url|uri"`
+	ID       string            `json:"id,omitempty" binding:"uuid4"`
+	Age      int               `json:"age,omitempty" validate:"gte=0,lt=130"`
+	Level    int               `json:"level,omitempty" validate:"oneof=1 2 3"`
+	Tags     []string          `json:"tags,omitempty" validate:"max=3,unique,dive,min=2"`
+	Nickname string            `json:"nickname,omitempty" validate:"min=2,max=8" jsonschema:"maxLength=12"`
+	Role     string            `json:"role,omitempty" validate:"oneof=a b" jsonschema:"enum=admin,enum=user"`
+	Labels   map[string]string `json:"labels,omitempty" validate:"max=5"`
+	Ratio    float64           `json:"ratio,omitempty" validate:"min=1.5,lt=2.5"`
+	Plan     string            `json:"plan,omitempty" validate:"oneof='free trial' pro"`
+}
+
+func TestValidatorTags(t *testing.T) {
+	r := &Reflector{ValidatorTags: []string{"validate", "binding"}}
+	b, err := json.Marshal(r.Reflect(&ValidateTagsUser{}).Definitions["ValidateTagsUser"])
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"type": "object",
+		"required": ["name", "email"],
+		"properties": {
+			"name": {"type": "string", "minLength": 1, "maxLength": 20},
+			"email": {"type": "string", "format": "email"},
+			"color": {"type": "string", "enum": ["red", "green"]},
+			"code": {"type": "string", "minLength": 3, "maxLength": 3, "pattern": "^[a-zA-Z]+$"},
+			"website": {"type": "string", "format": "uri"},
+			"id": {"type": "string", "format": "uuid"},
+			"age": {"type": "integer", "minimum": 0, "maximum": 130, "exclusiveMaximum": true},
+			"level": {"type": "integer", "enum": [1, 2, 3]},
+			"tags": {"type": "array", "maxItems": 3, "uniqueItems": true, "items": {"type": "string", "minLength": 2}},
+			"nickname": {"type": "string", "minLength": 2, "maxLength": 12},
+			"role": {"type": "string", "enum": ["admin", "user"]},
+			"labels": {"type": "object", "maxProperties": 5, "patternProperties": {".*": {"type": "string"}}},
+			"ratio": {"type": "number", "minimum": 1.5, "maximum": 2.5, "exclusiveMaximum": true},
+			"plan": {"type": "string", "enum": ["free trial", "pro"]}
+		},
+		"additionalProperties": false
+	}`, string(b))
+
+	s := Reflect(&ValidateTagsUser{})
+	name, _ := s.Definitions["ValidateTagsUser"].Properties.Get("name")
+	require.Equal(t, &Type{Type: "string"}, name, "validate tags are ignored by default")
+
+	schema := r.Reflect(&ValidateTagsUser{})
+	require.NoError(t, Validate(schema, []byte(`{"name": "joe", "email": "joe@example.com", "age": 0}`)))
+	require.Error(t, Validate(schema, []byte(`{"name": "joe", "email": "joe@example.com", "age": -1}`)))
+	require.Error(t, Validate(schema, []byte(`{"name": "joe", "email": "joe@example.com", "ratio": 2.5}`)))
+}
+
+type ValidateTagsUnknown struct {
+	A string `validate:"required_if=B x"`
+}
+
+type ValidateTagsFormat struct {
+	A int `validate:"email"`
+}
+
+type ValidateTagsLength struct {
+	A string `validate:"min=1.5"`
+}
+
+type ValidateTagsAlternatives struct {
+	A string `validate:"email|uuid"`
+}
+
+type ValidateTagsDive struct {
+	A string `validate:"dive,min=1"`
+}
+
+type ValidateTagsTime struct {
+	A time.Time `validate:"gt"`
+}
+
+func TestValidatorTagsUntranslatable(t *testing.T) {
+	for _, test := range []struct {
+		value interface{}
+		err   string
+	}{
+		{&ValidateTagsUnknown{}, `ValidateTagsUnknown.A: cannot translate validate rule "required_if=B x": no keyword corresponds to it for string`},
+		{&ValidateTagsFormat{}, `ValidateTagsFormat.A: cannot translate validate rule "email": no keyword corresponds to it for integer`},
+		{&ValidateTagsLength{}, `ValidateTagsLength.A: cannot translate validate rule "min=1.5": "1.5" is not a length`},
+		{&ValidateTagsAlternatives{}, `ValidateTagsAlternatives.A: cannot translate validate rule "email|uuid": alternatives email and uuid imply different keywords`},
+		{&ValidateTagsDive{}, `ValidateTagsDive.A: cannot translate validate rule "dive": dive only applies to arrays, not string`},
+		{&ValidateTagsTime{}, `ValidateTagsTime.A: cannot translate validate rule "gt": no keyword corresponds to it for string`},
+	} {
+		r := &Reflector{ValidatorTags: []string{"validate"}}
+		func() {
+			defer func() {
+				err, _ := recover().(error)
+				require.EqualError(t, err, "jsonschema: jsonschema."+test.err)
+			}()
+			r.Reflect(test.value)
+		}()
+	}
+}
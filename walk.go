@@ -0,0 +1,239 @@
+package jsonschema
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/iancoleman/orderedmap"
+)
+
+// children returns the schemas directly nested beneath t, in a stable order.
+func (t *Type) children() []*Type {
+	var out []*Type
+	add := func(c *Type) {
+		if c != nil {
+			out = append(out, c)
+		}
+	}
+	add(t.AdditionalItems)
+	add(t.Items)
+	if t.Properties != nil {
+		for _, key := range t.Properties.Keys() {
+			v, _ := t.Properties.Get(key)
+			if p, ok := v.(*Type); ok {
+				add(p)
+			}
+		}
+	}
+	for _, key := range sortedKeys(t.PatternProperties) {
+		add(t.PatternProperties[key])
+	}
+	for _, key := range sortedKeys(t.Dependencies) {
+		add(t.Dependencies[key])
+	}
+	for _, c := range t.AllOf {
+		add(c)
+	}
+	for _, c := range t.AnyOf {
+		add(c)
+	}
+	for _, c := range t.OneOf {
+		add(c)
+	}
+	add(t.Not)
+	for _, key := range sortedKeys(t.Definitions) {
+		add(t.Definitions[key])
+	}
+	add(t.Media)
+	return out
+}
+
+// mapChildren replaces each schema directly nested beneath t with the result
+// of fn, in the order of children.
+func (t *Type) mapChildren(fn func(*Type) *Type) {
+	apply := func(c *Type) *Type {
+		if c == nil {
+			return nil
+		}
+		return fn(c)
+	}
+	t.AdditionalItems = apply(t.AdditionalItems)
+	t.Items = apply(t.Items)
+	if t.Properties != nil {
+		for _, key := range t.Properties.Keys() {
+			v, _ := t.Properties.Get(key)
+			if p, ok := v.(*Type); ok {
+				t.Properties.Set(key, apply(p))
+			}
+		}
+	}
+	for _, m := range []map[string]*Type{t.PatternProperties, t.Dependencies} {
+		for _, key := range sortedKeys(m) {
+			m[key] = apply(m[key])
+		}
+	}
+	for _, list := range [][]*Type{t.AllOf, t.AnyOf, t.OneOf} {
+		for i, c := range list {
+			list[i] = apply(c)
+		}
+	}
+	t.Not = apply(t.Not)
+	for _, key := range sortedKeys(t.Definitions) {
+		t.Definitions[key] = apply(t.Definitions[key])
+	}
+	t.Media = apply(t.Media)
+}
+
+// walk calls fn for t and every schema nested beneath it, depth first.
+func (t *Type) walk(fn func(*Type)) {
+	if t == nil {
+		return
+	}
+	fn(t)
+	for _, c := range t.children() {
+		c.walk(fn)
+	}
+}
+
+// walk calls fn for the root type and every definition of the schema.
+func (s *Schema) walk(fn func(*Type)) {
+	s.Type.walk(fn)
+	for _, key := range sortedKeys(s.Definitions) {
+		s.Definitions[key].walk(fn)
+	}
+}
+
+func sortedKeys(m map[string]*Type) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// pointerEscape escapes a single JSON Pointer reference token (RFC 6901).
+func pointerEscape(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+// definitionName returns the definition a local $ref points to.
+func definitionName(ref string) (string, bool) {
+	if !strings.HasPrefix(ref, "#/definitions/") {
+		return "", false
+	}
+	return strings.TrimPrefix(ref, "#/definitions/"), true
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// resolve follows local references from t into d, returning nil if a
+// reference cannot be resolved.
+func (d Definitions) resolve(t *Type) *Type {
+	for i := 0; t != nil && t.Ref != "" && i <= len(d); i++ {
+		name, ok := definitionName(t.Ref)
+		if !ok {
+			return nil
+		}
+		t = d[name]
+	}
+	if t != nil && t.Ref != "" {
+		return nil
+	}
+	return t
+}
+
+// hasProperty reports whether t declares the property key.
+func (t *Type) hasProperty(key string) bool {
+	if t.Properties == nil {
+		return false
+	}
+	_, ok := t.Properties.Get(key)
+	return ok
+}
+
+// reachableDefinitions returns the names of the definitions of s that are
+// reachable from its root type through local $refs.
+func (s *Schema) reachableDefinitions() map[string]bool {
+	seen := map[string]bool{}
+	var visit func(t *Type)
+	visit = func(t *Type) {
+		t.walk(func(t *Type) {
+			name, ok := definitionName(t.Ref)
+			if !ok || seen[name] {
+				return
+			}
+			seen[name] = true
+			if def := s.Definitions[name]; def != nil {
+				visit(def)
+			}
+		})
+	}
+	visit(s.Type)
+	return seen
+}
+
+// clone returns a deep copy of t. Extras and enum, default and example values
+// are shared, as they are not schemas.
+func (t *Type) clone() *Type {
+	if t == nil {
+		return nil
+	}
+	c := *t
+	c.AdditionalItems = t.AdditionalItems.clone()
+	c.Items = t.Items.clone()
+	c.Not = t.Not.clone()
+	c.Media = t.Media.clone()
+	c.Required = append([]string(nil), t.Required...)
+	if t.Properties != nil {
+		c.Properties = orderedmap.New()
+		for _, key := range t.Properties.Keys() {
+			v, _ := t.Properties.Get(key)
+			if p, ok := v.(*Type); ok {
+				v = p.clone()
+			}
+			c.Properties.Set(key, v)
+		}
+	}
+	c.PatternProperties = cloneTypeMap(t.PatternProperties)
+	c.Dependencies = cloneTypeMap(t.Dependencies)
+	c.Definitions = Definitions(cloneTypeMap(t.Definitions))
+	c.AllOf = cloneTypes(t.AllOf)
+	c.AnyOf = cloneTypes(t.AnyOf)
+	c.OneOf = cloneTypes(t.OneOf)
+	return &c
+}
+
+// clone returns a deep copy of s.
+func (s *Schema) clone() *Schema {
+	return &Schema{Type: s.Type.clone(), Definitions: Definitions(cloneTypeMap(s.Definitions))}
+}
+
+func cloneTypeMap(m map[string]*Type) map[string]*Type {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]*Type, len(m))
+	for k, v := range m {
+		out[k] = v.clone()
+	}
+	return out
+}
+
+func cloneTypes(types []*Type) []*Type {
+	if types == nil {
+		return nil
+	}
+	out := make([]*Type, len(types))
+	for i, t := range types {
+		out[i] = t.clone()
+	}
+	return out
+}